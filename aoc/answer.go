@@ -0,0 +1,150 @@
+package aoc
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// Answer is the answer to a part: an int as long as it fits in one, a big.Int beyond
+// Products of large numbers overflow an int without a word, so they are computed
+// with Mul or Product that switch to math/big when needed
+// The zero value is the answer 0
+type Answer struct {
+	small int
+	// Only set when the answer does not fit in an int
+	big *big.Int
+}
+
+// Int returns the answer n
+func Int(n int) Answer {
+	return Answer{small: n}
+}
+
+// Big returns the answer n. It is kept as an int when it fits in one
+func Big(n *big.Int) Answer {
+	if n.IsInt64() && n.Int64() >= math.MinInt && n.Int64() <= math.MaxInt {
+		return Answer{small: int(n.Int64())}
+	}
+	return Answer{big: new(big.Int).Set(n)}
+}
+
+// IntAnswer turns the int returned by a solver into an answer
+// It is used by the solutions whose answers can't overflow, like counts
+func IntAnswer(n int, err error) (Answer, error) {
+	return Int(n), err
+}
+
+// Product returns the product of the factors, that can be larger than an int
+func Product(factors ...int) Answer {
+	product := Int(1)
+	for _, factor := range factors {
+		product = product.Mul(Int(factor))
+	}
+	return product
+}
+
+// Int returns the answer as an int, and false when it does not fit in one
+func (a Answer) Int() (int, bool) {
+	return a.small, a.big == nil
+}
+
+// Big returns the answer as a big.Int
+func (a Answer) Big() *big.Int {
+	if a.big == nil {
+		return big.NewInt(int64(a.small))
+	}
+	return new(big.Int).Set(a.big)
+}
+
+// Mul returns a * b
+func (a Answer) Mul(b Answer) Answer {
+	if a.big == nil && b.big == nil {
+		if product, ok := mulInts(a.small, b.small); ok {
+			return Int(product)
+		}
+	}
+	return Big(new(big.Int).Mul(a.Big(), b.Big()))
+}
+
+// Add returns a + b
+func (a Answer) Add(b Answer) Answer {
+	if a.big == nil && b.big == nil {
+		if sum, ok := addInts(a.small, b.small); ok {
+			return Int(sum)
+		}
+	}
+	return Big(new(big.Int).Add(a.Big(), b.Big()))
+}
+
+// Sub returns a - b
+func (a Answer) Sub(b Answer) Answer {
+	if a.big == nil && b.big == nil {
+		if difference, ok := subInts(a.small, b.small); ok {
+			return Int(difference)
+		}
+	}
+	return Big(new(big.Int).Sub(a.Big(), b.Big()))
+}
+
+// Equal tells whether both answers are the same number
+func (a Answer) Equal(b Answer) bool {
+	if a.big == nil && b.big == nil {
+		return a.small == b.small
+	}
+	return a.Big().Cmp(b.Big()) == 0
+}
+
+func (a Answer) String() string {
+	if a.big == nil {
+		return fmt.Sprint(a.small)
+	}
+	return a.big.String()
+}
+
+// MarshalJSON writes the answer as a JSON number, however large
+func (a Answer) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads an answer written as a JSON number
+func (a *Answer) UnmarshalJSON(data []byte) error {
+	n, ok := new(big.Int).SetString(string(data), 10)
+	if !ok {
+		return fmt.Errorf("invalid answer %s", data)
+	}
+	*a = Big(n)
+	return nil
+}
+
+// Returns a * b, and false when it overflows
+func mulInts(a int, b int) (int, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+	// -MinInt is larger than MaxInt, and MinInt / -1 overflows as well, so the
+	// division below can't catch it
+	if (a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) {
+		return 0, false
+	}
+	product := a * b
+	return product, product/b == a
+}
+
+// Returns a + b, and false when it overflows
+func addInts(a int, b int) (int, bool) {
+	sum := a + b
+	if (a > 0 && b > 0 && sum < 0) || (a < 0 && b < 0 && sum >= 0) {
+		return 0, false
+	}
+	return sum, true
+}
+
+// Returns a - b, and false when it overflows
+func subInts(a int, b int) (int, bool) {
+	difference := a - b
+	if (b > 0 && difference > a) || (b < 0 && difference < a) {
+		return 0, false
+	}
+	return difference, true
+}
@@ -0,0 +1,99 @@
+package aoc
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestProduct(t *testing.T) {
+	tests := []struct {
+		factors  []int
+		expected string
+		fits     bool
+	}{
+		{nil, "1", true},
+		{[]int{1604850}, "1604850", true},
+		{[]int{1131, -1006}, "-1137786", true},
+		{[]int{0, math.MaxInt}, "0", true},
+		{[]int{math.MaxInt, 2}, "18446744073709551614", false},
+		{[]int{math.MinInt, -1}, "9223372036854775808", false},
+		{[]int{-1, math.MinInt}, "9223372036854775808", false},
+		{[]int{math.MinInt, 1}, "-9223372036854775808", true},
+		{[]int{1 << 32, 1 << 32, 1 << 32}, "79228162514264337593543950336", false},
+		// Back to an int once multiplied by 0
+		{[]int{math.MaxInt, math.MaxInt, 0}, "0", true},
+	}
+	for _, test := range tests {
+		answer := Product(test.factors...)
+		if answer.String() != test.expected {
+			t.Errorf("Product(%v) = %s, expected %s", test.factors, answer, test.expected)
+		}
+		if _, fits := answer.Int(); fits != test.fits {
+			t.Errorf("Product(%v) fits in an int: %t, expected %t", test.factors, fits, test.fits)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	sum := Int(math.MaxInt).Add(Int(1))
+	if sum.String() != "9223372036854775808" {
+		t.Errorf("MaxInt + 1 = %s", sum)
+	}
+	back := sum.Add(Int(-1))
+	if n, ok := back.Int(); !ok || n != math.MaxInt {
+		t.Errorf("MaxInt + 1 - 1 = %s, fits in an int: %t", back, ok)
+	}
+	if sum := Int(math.MinInt).Add(Int(-1)); sum.String() != "-9223372036854775809" {
+		t.Errorf("MinInt - 1 = %s", sum)
+	}
+}
+
+func TestSub(t *testing.T) {
+	difference := Int(math.MinInt).Sub(Int(1))
+	if difference.String() != "-9223372036854775809" {
+		t.Errorf("MinInt - 1 = %s", difference)
+	}
+	if back := difference.Sub(Int(-1)); !back.Equal(Int(math.MinInt)) {
+		t.Errorf("MinInt - 1 + 1 = %s", back)
+	}
+	if difference := Int(0).Sub(Int(math.MinInt)); difference.String() != "9223372036854775808" {
+		t.Errorf("0 - MinInt = %s", difference)
+	}
+	if difference := Int(-1).Sub(Int(math.MaxInt)); !difference.Equal(Int(math.MinInt)) {
+		t.Errorf("-1 - MaxInt = %s", difference)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	large, _ := new(big.Int).SetString("18446744073709551614", 10)
+	if !Product(math.MaxInt, 2).Equal(Big(large)) {
+		t.Error("equal large answers are different")
+	}
+	if Product(math.MaxInt, 2).Equal(Int(-2)) {
+		t.Error("a large answer equals its overflowed value")
+	}
+	if !Big(big.NewInt(42)).Equal(Int(42)) || !(Answer{}).Equal(Int(0)) {
+		t.Error("equal small answers are different")
+	}
+}
+
+func TestJSON(t *testing.T) {
+	for _, answer := range []Answer{Int(0), Int(-42), Product(math.MaxInt, math.MaxInt)} {
+		data, err := json.Marshal(answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != answer.String() {
+			t.Errorf("got JSON %s for %s", data, answer)
+		}
+		var decoded Answer
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatal(err)
+		}
+		if !decoded.Equal(answer) {
+			t.Errorf("got %s back from %s", decoded, data)
+		}
+	}
+}
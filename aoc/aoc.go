@@ -0,0 +1,107 @@
+// Package aoc holds what all the days have in common: the way a day describes
+// how to parse its input and solve its parts, and the registry in which every
+// day registers itself so the aoc command can find it
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+)
+
+// Solution is one way of solving a part of a day
+// A part can have several solutions, the first one is the reference one, the
+// others are alternatives that give the same answer, usually written to
+// compare performance
+// Solve stops and returns the error of the context when the context is done
+type Solution struct {
+	Name  string
+	Solve func(ctx context.Context, puzzle interface{}) (Answer, error)
+}
+
+// CheckEvery is how often, in iterations of their loops, the solvers check whether
+// their context is done. Checking at every iteration would cost more than the work
+const CheckEvery = 1 << 12
+
+// Canceled returns the error of the context when it is done, but only checks it
+// once every CheckEvery iterations. i is the number of the iteration in the loop
+// Loops of the solvers start with
+//
+//	if err := aoc.Canceled(ctx, i); err != nil {
+//		return 0, err
+//	}
+func Canceled(ctx context.Context, i int) error {
+	if i%CheckEvery != 0 {
+		return nil
+	}
+	return ctx.Err()
+}
+
+// Day describes the challenge of one day
+type Day struct {
+	Number int
+	// Parse turns the content of the input file into the structure the parts work on
+	// The parts must not modify that structure, so it can be solved several times
+	Parse func(data []byte) (interface{}, error)
+	Part1 []Solution
+	Part2 []Solution
+	// Generate returns a synthetic input made of n records (lines, grids, etc)
+	// It is used to measure performance on inputs larger than the real ones
+	Generate func(n int) []byte
+	// Visualize animates the solving of the puzzle, calling show with every frame
+	// It stops with the error of show when show fails. It is nil for days that
+	// cannot be animated
+	Visualize func(ctx context.Context, puzzle interface{}, show func(frame string) error) error
+	// Files embedded in the binary: the example of the puzzle in example.txt,
+	// and the puzzle input in input.txt if it was there when building
+	Files fs.FS
+}
+
+// ErrNotEmbedded is returned when asking for a file that was not embedded in the binary
+var ErrNotEmbedded = errors.New("file not embedded in the binary")
+
+// File returns the content of a file embedded for the day, like "input.txt" or "example.txt"
+func (d Day) File(name string) ([]byte, error) {
+	if d.Files == nil {
+		return nil, fmt.Errorf("day %d %s: %w", d.Number, name, ErrNotEmbedded)
+	}
+	data, err := fs.ReadFile(d.Files, name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("day %d %s: %w", d.Number, name, ErrNotEmbedded)
+	}
+	return data, err
+}
+
+// Parts returns the solutions of both parts, part 1 at index 0 and part 2 at index 1
+func (d Day) Parts() [2][]Solution {
+	return [2][]Solution{d.Part1, d.Part2}
+}
+
+var registry = make(map[int]Day)
+
+// Register adds a day to the registry. Days call it from their init function
+// Registering the same day twice is a programming error, so it panics
+func Register(d Day) {
+	if _, ok := registry[d.Number]; ok {
+		panic("aoc: day registered twice")
+	}
+	registry[d.Number] = d
+}
+
+// Lookup returns the day registered with the given number
+func Lookup(number int) (Day, bool) {
+	d, ok := registry[number]
+	return d, ok
+}
+
+// Days returns all the registered days, in order
+func Days() []Day {
+	days := make([]Day, 0, len(registry))
+	for _, d := range registry {
+		days = append(days, d)
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Number < days[j].Number })
+	return days
+}
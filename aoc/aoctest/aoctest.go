@@ -0,0 +1,162 @@
+// Package aoctest holds the checks and benchmarks the tests of each day share,
+// apart from the aoc package so the binaries don't link the testing package
+package aoctest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Example is an input file of a day, usually the example of the puzzle, with
+// the answers of its parts
+type Example struct {
+	File         string
+	Part1, Part2 int
+}
+
+// CheckExamples runs every solution of the day with the given number on the
+// examples, read from the files of the day, and fails the test on wrong answers
+func CheckExamples(t *testing.T, number int, examples []Example) {
+	t.Helper()
+	day := mustLookup(t, number)
+	for _, example := range examples {
+		t.Run(example.File, func(t *testing.T) {
+			data, err := day.File(example.File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := aoc.Run(context.Background(), day, data, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for index, expected := range []int{example.Part1, example.Part2} {
+				part := result.Parts[index]
+				if part.Err != nil || !part.Answer.Equal(aoc.Int(expected)) {
+					t.Errorf("part %d: got %s (error %v), expected %d", index+1, part.Answer, part.Err, expected)
+				}
+			}
+		})
+	}
+}
+
+// CheckSolutions fails the test when a solution of the day with the given number
+// disagrees with the reference solution of its part on the given input
+// It is meant to be called from the tests of each day, with a synthetic input
+func CheckSolutions(t *testing.T, number int, data []byte) {
+	t.Helper()
+	day, ok := aoc.Lookup(number)
+	if !ok {
+		t.Fatalf("day %d is not registered", number)
+	}
+	disagreements, err := aoc.Verify(context.Background(), day, data, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, disagreement := range disagreements {
+		t.Error(disagreement)
+	}
+}
+
+// CheckCanceled fails the test when a solution of the day with the given number
+// does not stop with the error of its context when the context is already done
+func CheckCanceled(t *testing.T, number int, data []byte) {
+	t.Helper()
+	day := mustLookup(t, number)
+	puzzle, err := day.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for index, solutions := range day.Parts() {
+		for _, solution := range solutions {
+			if _, err := solution.Solve(ctx, puzzle); !errors.Is(err, context.Canceled) {
+				t.Errorf("part %d, solution %s: got error %v, expected %v", index+1, solution.Name, err, context.Canceled)
+			}
+		}
+	}
+}
+
+// CheckVisualize fails the test when the animation of the day with the given
+// number fails on the given input, shows no frame, or does not stop when show fails
+func CheckVisualize(t *testing.T, number int, data []byte) {
+	t.Helper()
+	day := mustLookup(t, number)
+	puzzle, err := day.Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := 0
+	err = day.Visualize(context.Background(), puzzle, func(frame string) error {
+		if frame == "" {
+			t.Errorf("frame %d is empty", frames+1)
+		}
+		frames++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if frames == 0 {
+		t.Fatal("no frame shown")
+	}
+
+	stop := errors.New("stop")
+	frames = 0
+	err = day.Visualize(context.Background(), puzzle, func(frame string) error {
+		frames++
+		return stop
+	})
+	if err != stop || frames != 1 {
+		t.Errorf("got error %v after %d frames, expected the error of show after the first frame", err, frames)
+	}
+}
+
+// BenchmarkParse measures the parsing of the given input by the day with the given number
+// It is meant to be called from the benchmarks of each day
+func BenchmarkParse(b *testing.B, number int, data []byte) {
+	day := mustLookup(b, number)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := day.Parse(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkPart measures every solution of a part (1 or 2) of the day with the
+// given number, one sub-benchmark per solution, all on the given input
+// It is meant to be called from the benchmarks of each day
+func BenchmarkPart(b *testing.B, number int, part int, data []byte) {
+	day := mustLookup(b, number)
+	puzzle, err := day.Parse(data)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, solution := range day.Parts()[part-1] {
+		solve := solution.Solve
+		b.Run(solution.Name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := solve(ctx, puzzle); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func mustLookup(tb testing.TB, number int) aoc.Day {
+	tb.Helper()
+	day, ok := aoc.Lookup(number)
+	if !ok {
+		tb.Fatalf("day %d is not registered", number)
+	}
+	return day
+}
@@ -0,0 +1,26 @@
+package aoc
+
+import (
+	"fmt"
+)
+
+// ParseError reports what is wrong in an input file, and where
+type ParseError struct {
+	// Line and column where the problem is, both start at 1
+	Line int
+	Col  int
+	Msg  string
+	// Err is the error that caused the problem, if any, like the error of strconv.Atoi
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("line %d, column %d: %s: %s", e.Line, e.Col, e.Msg, e.Err)
+	}
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Col, e.Msg)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
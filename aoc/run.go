@@ -0,0 +1,161 @@
+package aoc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// Measure is what it cost to run a step: parsing the input or solving a part
+type Measure struct {
+	Elapsed time.Duration
+	// Bytes allocated on the heap during the step
+	// It is read from the memory statistics of the whole process, so it is
+	// only accurate when nothing else runs at the same time
+	Allocated uint64
+}
+
+// PartResult is the answer to a part, or the error that prevented finding it
+type PartResult struct {
+	Answer Answer
+	Err    error
+	Measure
+}
+
+// Result is the outcome of running both parts of a day on an input
+type Result struct {
+	Parse Measure
+	Parts [2]PartResult
+}
+
+// Run parses the input with the day's parser, then solves both parts with
+// their reference solution, measuring each step
+// Each part is given at most timeout to find its answer, or forever when timeout is 0
+// The returned error is the parsing error, errors from the parts are in the result
+func Run(ctx context.Context, d Day, data []byte, timeout time.Duration) (Result, error) {
+	result, puzzle, err := parse(d, data)
+	if err != nil {
+		return result, err
+	}
+
+	for index, solutions := range d.Parts() {
+		result.Parts[index] = solvePart(ctx, solutions[0], puzzle, timeout)
+	}
+	return result, nil
+}
+
+// RunPart is the same as Run, for a single part (1 or 2)
+// The result of the other part is left empty
+func RunPart(ctx context.Context, d Day, data []byte, part int, timeout time.Duration) (Result, error) {
+	if part < 1 || part > 2 {
+		return Result{}, fmt.Errorf("invalid part %d, expected 1 or 2", part)
+	}
+	result, puzzle, err := parse(d, data)
+	if err != nil {
+		return result, err
+	}
+	result.Parts[part-1] = solvePart(ctx, d.Parts()[part-1][0], puzzle, timeout)
+	return result, nil
+}
+
+// Parses the input, measuring it
+func parse(d Day, data []byte) (Result, interface{}, error) {
+	var result Result
+	var puzzle interface{}
+	var err error
+	result.Parse = measure(func() {
+		puzzle, err = d.Parse(data)
+	})
+	return result, puzzle, err
+}
+
+// Solves a part with the given solution, measuring it
+func solvePart(ctx context.Context, solution Solution, puzzle interface{}, timeout time.Duration) PartResult {
+	var part PartResult
+	part.Measure = measure(func() {
+		part.Answer, part.Err = solve(ctx, solution, puzzle, timeout)
+	})
+	return part
+}
+
+// Solves a part with the given solution, in at most timeout unless it is 0
+func solve(ctx context.Context, solution Solution, puzzle interface{}, timeout time.Duration) (Answer, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	answer, err := solution.Solve(ctx, puzzle)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("solution %s timed out after %s: %w", solution.Name, timeout, err)
+	}
+	return answer, err
+}
+
+// Returns the time spent and the memory allocated running f
+func measure(f func()) Measure {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	f()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return Measure{elapsed, after.TotalAlloc - before.TotalAlloc}
+}
+
+// Disagreement is a solution whose outcome differs from the reference solution of its part
+type Disagreement struct {
+	Part      int
+	Reference string
+	Solution  string
+	// Answers and errors of the reference solution and of the one that disagrees
+	Expected    Answer
+	ExpectedErr error
+	Got         Answer
+	Err         error
+}
+
+func (d Disagreement) String() string {
+	return fmt.Sprintf("part %d: solution %s found %s, reference solution %s found %s",
+		d.Part, d.Solution, outcome(d.Got, d.Err), d.Reference, outcome(d.Expected, d.ExpectedErr))
+}
+
+func outcome(answer Answer, err error) string {
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	return answer.String()
+}
+
+// Verify parses the input, then solves each part with all its solutions and
+// returns those that disagree with the reference one: another answer, or an
+// error when the reference has none and the other way around
+// Each solution is given at most timeout to find its answer, or forever when timeout is 0
+// The returned error is the parsing error, or the error of the context when it is done
+func Verify(ctx context.Context, d Day, data []byte, timeout time.Duration) ([]Disagreement, error) {
+	puzzle, err := d.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	disagreements := make([]Disagreement, 0)
+	for index, solutions := range d.Parts() {
+		if len(solutions) == 0 {
+			continue
+		}
+		expected, expectedErr := solve(ctx, solutions[0], puzzle, timeout)
+		for _, solution := range solutions[1:] {
+			if err := ctx.Err(); err != nil {
+				return disagreements, err
+			}
+			got, err := solve(ctx, solution, puzzle, timeout)
+			if (err != nil) != (expectedErr != nil) || (err == nil && !got.Equal(expected)) {
+				disagreements = append(disagreements, Disagreement{
+					index + 1, solutions[0].Name, solution.Name, expected, expectedErr, got, err,
+				})
+			}
+		}
+	}
+	return disagreements, nil
+}
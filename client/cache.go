@@ -0,0 +1,101 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Cache is kept in a JSON file between runs of the aoc command
+// It remembers the verdicts of the answers already submitted and when the
+// website was last contacted, to respect its rate limits
+type Cache struct {
+	// Verdicts by account, day, part and answer, see verdictKey
+	Verdicts map[string]Verdict
+	// Time of the last request to the website
+	LastRequest time.Time
+	// The website refuses answers until that time
+	WaitUntil time.Time
+
+	path string
+}
+
+// LoadCache reads the cache from the given file. A missing file gives an empty cache
+func LoadCache(path string) (*Cache, error) {
+	cache := &Cache{Verdicts: make(map[string]Verdict), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if cache.Verdicts == nil {
+		cache.Verdicts = make(map[string]Verdict)
+	}
+	return cache, nil
+}
+
+// Save writes the cache to the file it was loaded from
+func (c *Cache) Save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o600)
+}
+
+// Verdict returns the verdict for an answer of the account, if it was already
+// submitted. The account is the one of a session cookie, see Account
+// When a part was solved, any other answer to that part is known to be
+// incorrect, for the same account: every account has its own input
+func (c *Cache) Verdict(account string, day int, part int, answer string) (Verdict, bool) {
+	if verdict, ok := c.Verdicts[verdictKey(account, day, part, answer)]; ok {
+		return verdict, true
+	}
+	for _, verdict := range c.Verdicts {
+		if verdict.Account == account && verdict.Day == day && verdict.Part == part && verdict.Status == Correct {
+			return Verdict{
+				Account: account,
+				Day:     day,
+				Part:    part,
+				Answer:  answer,
+				Status:  Incorrect,
+				Message: fmt.Sprintf("Already solved with %s", verdict.Answer),
+				Time:    verdict.Time,
+			}, true
+		}
+	}
+	return Verdict{}, false
+}
+
+// AddVerdict keeps a verdict. Only correct and incorrect answers are worth keeping,
+// the others would give a different verdict when submitted again
+func (c *Cache) AddVerdict(verdict Verdict) {
+	if verdict.Status == Correct || verdict.Status == Incorrect {
+		c.Verdicts[verdictKey(verdict.Account, verdict.Day, verdict.Part, verdict.Answer)] = verdict
+	}
+}
+
+// Account identifies the account of a session cookie in the cache, without
+// keeping the cookie itself in the file
+func Account(session string) string {
+	sum := sha256.Sum256([]byte(session))
+	return hex.EncodeToString(sum[:8])
+}
+
+func verdictKey(account string, day int, part int, answer string) string {
+	return fmt.Sprintf("%s/%d/%d/%s", account, day, part, answer)
+}
@@ -0,0 +1,157 @@
+// Package client talks to the Advent of Code website: it downloads the puzzle
+// inputs and submits the answers
+//
+// The website asks to be gentle with it, so the client waits between two
+// requests, and never submits twice the same answer: the verdicts are kept in
+// a local cache
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Year of the challenge
+const Year = 2021
+
+// DefaultBaseURL is the address of the Advent of Code website
+const DefaultBaseURL = "https://adventofcode.com"
+
+// The website asks automated tools to identify themselves
+const userAgent = "github.com/aymec/adventofcode2021"
+
+// ErrNoSession is returned when trying to reach the website without a session cookie
+var ErrNoSession = errors.New("no session cookie, set AOC_SESSION or write it in the session file")
+
+// Client downloads inputs and submits answers for a user, identified by the
+// session cookie of their browser
+type Client struct {
+	// BaseURL is the address of the website, DefaultBaseURL unless testing
+	BaseURL string
+	Session string
+	HTTP    *http.Client
+	// Cache keeps the verdicts and the time of the last request. It can be nil
+	Cache *Cache
+	// MinInterval is the minimum time between two requests to the website
+	MinInterval time.Duration
+
+	// Sleep waits between two requests, it is only replaced by tests
+	sleep func(time.Duration)
+}
+
+// New returns a client for the website at baseURL, using the given session cookie
+func New(baseURL string, session string, cache *Cache) *Client {
+	return &Client{
+		BaseURL:     strings.TrimSuffix(baseURL, "/"),
+		Session:     session,
+		HTTP:        &http.Client{Timeout: 30 * time.Second},
+		Cache:       cache,
+		MinInterval: 5 * time.Second,
+		sleep:       time.Sleep,
+	}
+}
+
+// Input downloads the puzzle input of a day
+func (c *Client) Input(day int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%d/day/%d/input", c.BaseURL, Year, day), nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// Submit sends the answer to a part of a day and returns the verdict of the website
+// An answer that was already judged is not sent again, its verdict comes from the cache
+func (c *Client) Submit(day int, part int, answer string) (Verdict, error) {
+	if c.Cache != nil {
+		if verdict, ok := c.Cache.Verdict(Account(c.Session), day, part, answer); ok {
+			return verdict, nil
+		}
+		if wait := time.Until(c.Cache.WaitUntil); wait > 0 {
+			return Verdict{}, &RateLimitError{wait}
+		}
+	}
+
+	form := url.Values{"level": {fmt.Sprint(part)}, "answer": {answer}}
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("%s/%d/day/%d/answer", c.BaseURL, Year, day), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	body, err := c.do(req)
+	if err != nil {
+		return Verdict{}, err
+	}
+
+	verdict := parseVerdict(body)
+	verdict.Account, verdict.Day, verdict.Part, verdict.Answer, verdict.Time = Account(c.Session), day, part, answer, time.Now()
+	if c.Cache != nil {
+		if verdict.Status == TooRecent {
+			c.Cache.WaitUntil = verdict.Time.Add(verdict.Wait)
+		} else {
+			c.Cache.AddVerdict(verdict)
+		}
+		if err := c.Cache.Save(); err != nil {
+			return verdict, err
+		}
+	}
+	return verdict, nil
+}
+
+// Sends a request to the website, waiting first if the previous one was too recent
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	if c.Session == "" {
+		return nil, ErrNoSession
+	}
+	if c.Cache != nil {
+		if wait := time.Until(c.Cache.LastRequest.Add(c.MinInterval)); wait > 0 {
+			c.sleep(wait)
+		}
+		c.Cache.LastRequest = time.Now()
+		if err := c.Cache.Save(); err != nil {
+			return nil, err
+		}
+	}
+
+	req.Header.Set("User-Agent", userAgent)
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.Session})
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{resp.StatusCode, strings.TrimSpace(string(body))}
+	}
+	return body, nil
+}
+
+// StatusError is returned when the website answers with an HTTP error
+// The website answers 400 when the session cookie is invalid, and 404 when
+// the puzzle is not unlocked yet
+type StatusError struct {
+	Code int
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("website answered %d %s: %s", e.Code, http.StatusText(e.Code), e.Body)
+}
+
+// RateLimitError is returned when submitting again before the delay given by the website
+type RateLimitError struct {
+	Wait time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("answer submitted too recently, wait %s before submitting again", e.Wait.Round(time.Second))
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A fake website that accepts 42 as the answer to every part
+func newServer(t *testing.T, submissions *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2021/day/1/input", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("session"); err != nil || c.Value != "secret" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, "199\n200\n208\n")
+	})
+	mux.HandleFunc("/2021/day/1/answer", func(w http.ResponseWriter, r *http.Request) {
+		*submissions++
+		switch r.FormValue("answer") {
+		case "42":
+			fmt.Fprint(w, "<main><article><p>That's the right answer!  You are one gold star closer.</p></article></main>")
+		case "wait":
+			fmt.Fprint(w, "<article><p>You gave an answer too recently. You have 1m 5s left to wait.</p></article>")
+		default:
+			fmt.Fprint(w, "<article><p>That's not the right answer; your answer is too low.</p></article>")
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newClient(t *testing.T, server *httptest.Server, session string) *Client {
+	cache, err := LoadCache(filepath.Join(t.TempDir(), "cache.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := New(server.URL, session, cache)
+	c.sleep = func(time.Duration) {}
+	return c
+}
+
+func TestInput(t *testing.T) {
+	server := newServer(t, new(int))
+
+	data, err := newClient(t, server, "secret").Input(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "199\n200\n208\n" {
+		t.Errorf("got input %q", data)
+	}
+
+	_, err = newClient(t, server, "wrong").Input(1)
+	if statusErr, ok := err.(*StatusError); !ok || statusErr.Code != http.StatusBadRequest {
+		t.Errorf("got error %v, expected a 400 status error", err)
+	}
+
+	if _, err = newClient(t, server, "").Input(1); err != ErrNoSession {
+		t.Errorf("got error %v, expected ErrNoSession", err)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	submissions := 0
+	c := newClient(t, newServer(t, &submissions), "secret")
+
+	tests := []struct {
+		answer      string
+		status      Status
+		submissions int
+	}{
+		{"12", Incorrect, 1},
+		// Already judged, not submitted again
+		{"12", Incorrect, 1},
+		{"42", Correct, 2},
+		// Part solved, every other answer is incorrect
+		{"13", Incorrect, 2},
+	}
+	for _, test := range tests {
+		verdict, err := c.Submit(1, 1, test.answer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verdict.Status != test.status || submissions != test.submissions {
+			t.Errorf("answer %s: got %s after %d submissions, expected %s after %d",
+				test.answer, verdict.Status, submissions, test.status, test.submissions)
+		}
+	}
+
+	// The cache is saved and loaded again
+	cache, err := LoadCache(c.Cache.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict, ok := cache.Verdict(Account("secret"), 1, 1, "42"); !ok || verdict.Status != Correct {
+		t.Errorf("got verdict %v from the saved cache", verdict)
+	}
+	// Other accounts have other inputs, their answers are not judged by the cache
+	if verdict, ok := cache.Verdict(Account("other"), 1, 1, "13"); ok {
+		t.Errorf("got verdict %v for another account", verdict)
+	}
+	if data, _ := os.ReadFile(c.Cache.path); strings.Contains(string(data), "secret") {
+		t.Errorf("the session is written in the cache")
+	}
+}
+
+func TestSubmitTooRecent(t *testing.T) {
+	submissions := 0
+	c := newClient(t, newServer(t, &submissions), "secret")
+
+	verdict, err := c.Submit(1, 2, "wait")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verdict.Status != TooRecent || verdict.Wait != 65*time.Second {
+		t.Errorf("got %s, wait %s", verdict.Status, verdict.Wait)
+	}
+
+	// Submitting again before the delay is refused without contacting the website
+	_, err = c.Submit(1, 2, "42")
+	if _, ok := err.(*RateLimitError); !ok || submissions != 1 {
+		t.Errorf("got error %v after %d submissions", err, submissions)
+	}
+}
+
+func TestMinInterval(t *testing.T) {
+	c := newClient(t, newServer(t, new(int)), "secret")
+	var waited time.Duration
+	c.sleep = func(d time.Duration) { waited += d }
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Input(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if waited <= 0 || waited > c.MinInterval {
+		t.Errorf("waited %s between 2 requests, expected up to %s", waited, c.MinInterval)
+	}
+}
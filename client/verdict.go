@@ -0,0 +1,70 @@
+package client
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Status is the judgement of the website on an answer
+type Status string
+
+const (
+	Correct   Status = "correct"
+	Incorrect Status = "incorrect"
+	// The previous answer was submitted too recently, this one was not judged
+	TooRecent Status = "too recent"
+	// The part is already solved, or the first part is not solved yet
+	WrongLevel Status = "wrong level"
+	Unknown    Status = "unknown"
+)
+
+// Verdict is what the website said about an answer
+type Verdict struct {
+	// Account is the account the answer was submitted for, see Account
+	Account string
+	Day     int
+	Part    int
+	Answer  string
+	Status  Status
+	// Message is the text of the website's response
+	Message string
+	// Wait is the time to wait before submitting again, when the status is TooRecent
+	Wait time.Duration `json:",omitempty"`
+	Time time.Time
+}
+
+var (
+	articleRe = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRe     = regexp.MustCompile(`<[^>]*>`)
+	spaceRe   = regexp.MustCompile(`\s+`)
+	// The website gives the time left like "You have 1m 5s left to wait" or "You have 35s left to wait"
+	waitRe = regexp.MustCompile(`You have (\d+m ?)?(\d+s) left to wait`)
+)
+
+// Reads the verdict in the HTML page returned by the website after submitting an answer
+func parseVerdict(page []byte) Verdict {
+	message := string(page)
+	if match := articleRe.FindStringSubmatch(message); match != nil {
+		message = match[1]
+	}
+	message = tagRe.ReplaceAllString(message, "")
+	message = strings.TrimSpace(spaceRe.ReplaceAllString(html.UnescapeString(message), " "))
+
+	verdict := Verdict{Status: Unknown, Message: message}
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		verdict.Status = Correct
+	case strings.Contains(message, "That's not the right answer"):
+		verdict.Status = Incorrect
+	case strings.Contains(message, "You gave an answer too recently"):
+		verdict.Status = TooRecent
+		if match := waitRe.FindStringSubmatch(message); match != nil {
+			verdict.Wait, _ = time.ParseDuration(strings.ReplaceAll(match[1], " ", "") + match[2])
+		}
+	case strings.Contains(message, "You don't seem to be solving the right level"):
+		verdict.Status = WrongLevel
+	}
+	return verdict
+}
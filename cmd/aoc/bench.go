@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+func benchCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("bench", flag.ExitOnError)
+	size := flags.Int("n", 10000, "number of records in the synthetic inputs")
+	path := flags.String("input", "", "benchmark on this input file instead of a synthetic input")
+	args = parseArgs(flags, args)
+
+	days := aoc.Days()
+	if len(args) > 0 {
+		days = days[:0:0]
+		for _, arg := range args {
+			day, err := dayFromArg(arg)
+			if err != nil {
+				return err
+			}
+			days = append(days, day)
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "day\tpart\tsolution\tns/op\tallocs/op\tB/op\t")
+	failed := 0
+	for _, day := range days {
+		var data []byte
+		if *path != "" {
+			var err error
+			if data, err = os.ReadFile(*path); err != nil {
+				return err
+			}
+		} else {
+			data = day.Generate(*size)
+		}
+
+		puzzle, err := day.Parse(data)
+		if err != nil {
+			return fmt.Errorf("day %d: %w", day.Number, err)
+		}
+		if !printBench(w, day.Number, "-", "parse", func() error {
+			_, err := day.Parse(data)
+			return err
+		}) {
+			failed++
+		}
+
+		for index, solutions := range day.Parts() {
+			for _, solution := range solutions {
+				solve := solution.Solve
+				if !printBench(w, day.Number, fmt.Sprint(index+1), solution.Name, func() error {
+					_, err := solve(ctx, puzzle)
+					return err
+				}) {
+					failed++
+				}
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d benchmarks failed", failed)
+	}
+	return nil
+}
+
+// Benchmarks run and prints a row with its result, or with its error when it
+// fails, in which case it returns false
+func printBench(w *tabwriter.Writer, day int, part string, name string, run func() error) bool {
+	var err error
+	r := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N && err == nil; i++ {
+			err = run()
+		}
+	})
+	if err != nil {
+		fmt.Fprintf(w, "%d\t%s\t%s\tFAIL: %v\t\t\t\n", day, part, name, err)
+		return false
+	}
+	fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%d\t%d\t\n", day, part, name, r.NsPerOp(), r.AllocsPerOp(), r.AllocedBytesPerOp())
+	return true
+}
@@ -0,0 +1,10 @@
+package main
+
+// Every day registers itself in the aoc registry when its package is imported
+import (
+	_ "github.com/aymec/adventofcode2021/day1"
+	_ "github.com/aymec/adventofcode2021/day2"
+	_ "github.com/aymec/adventofcode2021/day3"
+	_ "github.com/aymec/adventofcode2021/day4"
+	_ "github.com/aymec/adventofcode2021/day5"
+)
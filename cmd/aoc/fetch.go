@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/client"
+)
+
+// Where the client finds the website and the session cookie
+// The environment variables win over the files and the defaults
+type clientConfig struct {
+	baseURL string
+}
+
+func (c *clientConfig) register(flags *flag.FlagSet) {
+	baseURL := os.Getenv("AOC_BASE_URL")
+	if baseURL == "" {
+		baseURL = client.DefaultBaseURL
+	}
+	flags.StringVar(&c.baseURL, "base-url", baseURL, "address of the Advent of Code website (env AOC_BASE_URL)")
+}
+
+// Returns a client with the session cookie from the AOC_SESSION environment variable,
+// or from the session file in the user's configuration folder
+func (c *clientConfig) client() (*client.Client, error) {
+	session := os.Getenv("AOC_SESSION")
+	if session == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "adventofcode2021", "session"))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		session = strings.TrimSpace(string(data))
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	cache, err := client.LoadCache(filepath.Join(dir, "adventofcode2021", "cache.json"))
+	if err != nil {
+		return nil, err
+	}
+	return client.New(c.baseURL, session, cache), nil
+}
+
+func fetchCmd(_ context.Context, args []string) error {
+	flags := flag.NewFlagSet("fetch", flag.ExitOnError)
+	force := flags.Bool("force", false, "download the input even if dayN/input.txt already exists")
+	var config clientConfig
+	config.register(flags)
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc fetch N [flags]")
+	}
+	day, err := strconv.Atoi(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid day %q", args[0])
+	}
+
+	// The input never changes, no need to bother the website twice
+	path := fmt.Sprintf("day%d/input.txt", day)
+	if _, err := os.Stat(path); err == nil && !*force {
+		return fmt.Errorf("%s already exists, use --force to download it again", path)
+	}
+
+	c, err := config.client()
+	if err != nil {
+		return err
+	}
+	data, err := c.Input(day)
+	if err != nil {
+		return err
+	}
+	// The parsers expect no empty line at the end of the input
+	data = bytes.TrimRight(data, "\n")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	log.Printf("Input of day %d written to %s", day, path)
+	return nil
+}
+
+func submitCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("submit", flag.ExitOnError)
+	var in inputFlags
+	in.register(flags)
+	var config clientConfig
+	config.register(flags)
+	timeout := flags.Duration("timeout", 0, "stop solving the part after this long, like 10s (0 means no limit)")
+	args = parseArgs(flags, args)
+	if len(args) != 2 && len(args) != 3 {
+		return errors.New("usage: aoc submit N P [answer] [flags]")
+	}
+	day, err := dayFromArg(args[0])
+	if err != nil {
+		return err
+	}
+	part, err := strconv.Atoi(args[1])
+	if err != nil || part < 1 || part > 2 {
+		return fmt.Errorf("invalid part %q, expected 1 or 2", args[1])
+	}
+
+	// Without an answer on the command line, solve the part
+	var answer string
+	if len(args) == 3 {
+		answer = args[2]
+	} else {
+		data, name, err := in.read(day)
+		if err != nil {
+			return err
+		}
+		result, err := aoc.RunPart(ctx, day, data, part, *timeout)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := result.Parts[part-1].Err; err != nil {
+			return fmt.Errorf("part %d: %w", part, err)
+		}
+		answer = result.Parts[part-1].Answer.String()
+	}
+
+	c, err := config.client()
+	if err != nil {
+		return err
+	}
+	verdict, err := c.Submit(day.Number, part, answer)
+	if err != nil {
+		return err
+	}
+	log.Printf("Day %d part %d - %s: %s", day.Number, part, answer, verdict.Status)
+	log.Print(verdict.Message)
+	if verdict.Status != client.Correct {
+		return fmt.Errorf("answer %s", verdict.Status)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Where to read the input of a day from
+type inputFlags struct {
+	source string
+	path   string
+}
+
+func (f *inputFlags) register(flags *flag.FlagSet) {
+	flags.StringVar(&f.source, "source", "file", "where to read the input from: file, embedded (input.txt embedded in the binary), example or stdin")
+	flags.StringVar(&f.path, "input", "", "input file when the source is file (default dayN/input.txt)")
+}
+
+// Returns the input of the day, and a name for it to report errors
+func (f *inputFlags) read(day aoc.Day) ([]byte, string, error) {
+	switch f.source {
+	case "file":
+		// The input is in the folder of the day, the path is relative to the root folder
+		path := f.path
+		if path == "" {
+			path = fmt.Sprintf("day%d/input.txt", day.Number)
+		}
+		data, err := os.ReadFile(path)
+		return data, path, err
+	case "embedded":
+		data, err := day.File("input.txt")
+		return data, "embedded input.txt", err
+	case "example":
+		data, err := day.File("example.txt")
+		return data, "embedded example.txt", err
+	case "stdin":
+		data, err := io.ReadAll(os.Stdin)
+		return data, "stdin", err
+	}
+	return nil, "", fmt.Errorf("invalid source %q, expected file, embedded, example or stdin", f.source)
+}
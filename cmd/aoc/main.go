@@ -0,0 +1,86 @@
+// The aoc command runs the solvers of every day of the challenge
+//
+// Usage:
+//
+//	aoc run N       solve both parts of day N with the input in dayN/input.txt
+//	aoc bench [N]   compare the performance of the solutions of each part
+//	aoc fetch N     download the input of day N into dayN/input.txt
+//	aoc submit N P  submit the answer to part P of day N
+//	aoc new N       create the folder of day N and register it
+//	aoc serve       solve the puzzles sent over HTTP, see the server package
+//	aoc sonar       tools for the sonar readings of day 1, like counting in huge files
+//	aoc sub         tools for the submarine instructions of day 2, like scripts
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+)
+
+// A command of the aoc tool, like `run` or `bench`
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"run", "run N [flags]\tsolve both parts of day N", runCmd},
+	{"bench", "bench [flags] [N...]\tcompare the performance of the solutions of each part", benchCmd},
+	{"fetch", "fetch N [flags]\tdownload the input of day N into dayN/input.txt", fetchCmd},
+	{"submit", "submit N P [answer] [flags]\tsubmit the answer to part P of day N", submitCmd},
+	{"new", "new N\tcreate the folder of day N, with its solver and tests, and register it", newCmd},
+	{"serve", "serve [flags]\tsolve the puzzles sent over HTTP", serveCmd},
+	{"sonar", "sonar <command>\ttools for the sonar readings of day 1, run it alone for the list", sonarCmd},
+	{"sub", "sub <command>\ttools for the submarine instructions of day 2, run it alone for the list", subCmd},
+}
+
+func main() {
+	log.SetFlags(0)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	// Ctrl-C cancels the context, so the solvers stop and the command reports what it has
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	for _, cmd := range commands {
+		if cmd.name == os.Args[1] {
+			if err := cmd.run(ctx, os.Args[2:]); err != nil {
+				stop()
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+	usage()
+	os.Exit(2)
+}
+
+// Parses the flags wherever they are among the arguments, so `aoc run 5 --input file`
+// works as well as `aoc run --input file 5`. The flag package alone stops at the first
+// argument that is not a flag. Returns the arguments that are not flags
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	positional := make([]string, 0, len(args))
+	for {
+		flags.Parse(args)
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: aoc <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "\t%s\n", cmd.usage)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// The files of a new day, see newCmd
+//
+//go:embed templates
+var templates embed.FS
+
+const modulePath = "github.com/aymec/adventofcode2021"
+
+// The file importing all the days, so they register themselves
+const daysFile = "cmd/aoc/days.go"
+
+// Creates the folder of a new day, with a solver that does nothing yet, its tests
+// and an empty input file, and registers it in the aoc command
+func newCmd(_ context.Context, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: aoc new N")
+	}
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > 25 {
+		return fmt.Errorf("invalid day %q, expected 1 to 25", args[0])
+	}
+	if _, ok := aoc.Lookup(number); ok {
+		return fmt.Errorf("day %d already exists", number)
+	}
+	dir := fmt.Sprintf("day%d", number)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		"day.go.tmpl":      fmt.Sprintf("day%d.go", number),
+		"day_test.go.tmpl": fmt.Sprintf("day%d_test.go", number),
+		"generate.go.tmpl": "generate.go",
+	}
+	for name, file := range files {
+		tmpl, err := template.ParseFS(templates, "templates/"+name)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, struct{ Number int }{number}); err != nil {
+			return err
+		}
+		if err := writeGoFile(filepath.Join(dir, file), buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	for _, file := range []string{"input.txt", "example.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, file), nil, 0o644); err != nil {
+			return err
+		}
+	}
+
+	if err := registerDay(number); err != nil {
+		return err
+	}
+	log.Printf("Day %d created in %s and registered in %s", number, dir, daysFile)
+	return nil
+}
+
+// Adds the import of the new day to the file importing all the days
+func registerDay(number int) error {
+	src, err := os.ReadFile(daysFile)
+	if err != nil {
+		return err
+	}
+	// The imports are the last thing in the file
+	end := bytes.LastIndexByte(src, ')')
+	if end < 0 {
+		return fmt.Errorf("%s: no import block", daysFile)
+	}
+	line := fmt.Sprintf("\t_ %q\n", modulePath+"/day"+strconv.Itoa(number))
+	src = []byte(string(src[:end]) + line + string(src[end:]))
+	return writeGoFile(daysFile, src)
+}
+
+// Formats Go source, which also sorts the imports, and writes it to a file
+func writeGoFile(path string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	// Keep the days in numerical order, format.Source sorts day10 before day2
+	if path == daysFile {
+		formatted = sortDayImports(formatted)
+	}
+	return os.WriteFile(path, formatted, 0o644)
+}
+
+// Sorts the lines importing the days by day number
+func sortDayImports(src []byte) []byte {
+	lines := strings.Split(string(src), "\n")
+	prefix := "\t_ \"" + modulePath + "/day"
+	first, last := -1, -1
+	for index, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			if first < 0 {
+				first = index
+			}
+			last = index
+		}
+	}
+	if first < 0 {
+		return src
+	}
+	dayNumber := func(line string) int {
+		number, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, prefix), "\""))
+		return number
+	}
+	imports := lines[first : last+1]
+	for i := 1; i < len(imports); i++ {
+		for j := i; j > 0 && dayNumber(imports[j]) < dayNumber(imports[j-1]); j-- {
+			imports[j], imports[j-1] = imports[j-1], imports[j]
+		}
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// Files where to write the profiles, empty when not profiling
+type profiles struct {
+	cpu   string
+	mem   string
+	trace string
+}
+
+func (p *profiles) register(flags *flag.FlagSet) {
+	flags.StringVar(&p.cpu, "cpuprofile", "", "write a CPU profile to this file")
+	flags.StringVar(&p.mem, "memprofile", "", "write a memory profile to this file")
+	flags.StringVar(&p.trace, "trace", "", "write an execution trace to this file")
+}
+
+// Starts the CPU profile and the trace, if requested
+// The returned function stops them and writes the memory profile. It must be called
+// even when start returns an error, to stop what was already started
+func (p *profiles) start() (stop func() error, err error) {
+	var cpuFile, traceFile *os.File
+	stop = func() error {
+		if cpuFile != nil {
+			pprof.StopCPUProfile()
+			if err := cpuFile.Close(); err != nil {
+				return err
+			}
+		}
+		if traceFile != nil {
+			trace.Stop()
+			if err := traceFile.Close(); err != nil {
+				return err
+			}
+		}
+		if p.mem != "" {
+			f, err := os.Create(p.mem)
+			if err != nil {
+				return err
+			}
+			// Get up-to-date statistics
+			runtime.GC()
+			if err := pprof.WriteHeapProfile(f); err != nil {
+				f.Close()
+				return err
+			}
+			return f.Close()
+		}
+		return nil
+	}
+
+	if p.cpu != "" {
+		f, err := os.Create(p.cpu)
+		if err != nil {
+			return stop, err
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close()
+			return stop, err
+		}
+		cpuFile = f
+	}
+	if p.trace != "" {
+		f, err := os.Create(p.trace)
+		if err != nil {
+			return stop, err
+		}
+		if err := trace.Start(f); err != nil {
+			f.Close()
+			return stop, err
+		}
+		traceFile = f
+	}
+	return stop, nil
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/regress"
+)
+
+// Returns the registered day whose number is given as a command line argument
+func dayFromArg(arg string) (aoc.Day, error) {
+	number, err := strconv.Atoi(arg)
+	if err != nil {
+		return aoc.Day{}, fmt.Errorf("invalid day %q", arg)
+	}
+	day, ok := aoc.Lookup(number)
+	if !ok {
+		return aoc.Day{}, fmt.Errorf("day %d is not solved yet", number)
+	}
+	return day, nil
+}
+
+// An input to run the day on, and what came out of it
+type run struct {
+	name string
+	// Path of the file to read the input from, when data was not read yet
+	path   string
+	data   []byte
+	result aoc.Result
+	// Error reading or parsing the input
+	err error
+	// Solutions that disagree with the reference ones, with --verify
+	disagreements []aoc.Disagreement
+}
+
+func runCmd(ctx context.Context, args []string) (err error) {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	var in inputFlags
+	in.register(flags)
+	inputs := flags.String("inputs", "", "run on all the files matching this pattern, like 'inputs/day5/*.txt'")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of inputs solved at the same time with --inputs")
+	var prof profiles
+	prof.register(flags)
+	var vis visualizeFlags
+	vis.register(flags)
+	answersPath := flags.String("answers", "answers.txt", "file where the answers are recorded, to detect when they change")
+	update := flags.Bool("update", false, "record the new answers when they changed")
+	verify := flags.Bool("verify", false, "run all the solutions of each part and fail when they disagree")
+	timeout := flags.Duration("timeout", 0, "stop each solution after this long, like 10s (0 means no limit)")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc run N [flags]")
+	}
+	day, err := dayFromArg(args[0])
+	if err != nil {
+		return err
+	}
+
+	runs := make([]*run, 0)
+	if *inputs != "" {
+		paths, err := filepath.Glob(*inputs)
+		if err != nil {
+			return err
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no file matches %s", *inputs)
+		}
+		for _, path := range paths {
+			runs = append(runs, &run{name: path, path: path})
+		}
+	} else {
+		data, name, err := in.read(day)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, &run{name: name, data: data})
+	}
+	if *workers < 1 {
+		*workers = 1
+	}
+	if vis.enabled {
+		if len(runs) > 1 {
+			return errors.New("--visualize animates a single input, it cannot be used with --inputs")
+		}
+		if in.source == "stdin" {
+			return errors.New("--visualize reads its commands from the standard input, it cannot be the input")
+		}
+		if err := vis.play(ctx, day, runs[0].data); err != nil {
+			return err
+		}
+	}
+
+	stop, err := prof.start()
+	defer func() {
+		if stopErr := stop(); err == nil {
+			err = stopErr
+		}
+	}()
+	if err != nil {
+		return err
+	}
+
+	runAll(ctx, day, runs, *workers, *verify, *timeout)
+
+	// Report in the order of the inputs, whatever the order they were solved in
+	db, err := regress.Load(*answersPath)
+	if err != nil {
+		return err
+	}
+	failed, disagreements := 0, 0
+	mismatches := make([]regress.Mismatch, 0)
+	for _, r := range runs {
+		if len(runs) > 1 {
+			log.Printf("== %s", r.name)
+		}
+		if r.data != nil {
+			log.Printf("Parse  - %s", formatMeasure(r.result.Parse))
+		}
+		if r.err != nil {
+			log.Printf("%s: %s", r.name, r.err)
+			failed++
+			continue
+		}
+		stopped := false
+		for index, part := range r.result.Parts {
+			if part.Err != nil {
+				log.Printf("Part %d - %s (%s)", index+1, part.Err, formatMeasure(part.Measure))
+				stopped = stopped || interrupted(part.Err)
+			} else {
+				log.Printf("Part %d - %s (%s)", index+1, part.Answer, formatMeasure(part.Measure))
+			}
+		}
+		for _, disagreement := range r.disagreements {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", r.name, disagreement)
+		}
+		disagreements += len(r.disagreements)
+		if stopped {
+			failed++
+		}
+		mismatches = append(mismatches, checkAnswers(db, *update, day.Number, regress.Hash(r.data), r.result)...)
+	}
+	if err := db.Save(); err != nil {
+		return err
+	}
+
+	for _, mismatch := range mismatches {
+		fmt.Fprintln(os.Stderr, mismatch)
+	}
+	if len(mismatches) > 0 && !*update {
+		return fmt.Errorf("%d answer(s) changed since they were recorded in %s, use --update to record the new ones", len(mismatches), *answersPath)
+	}
+	if disagreements > 0 {
+		return fmt.Errorf("%d solution(s) disagree with the reference solutions", disagreements)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d inputs failed", failed, len(runs))
+	}
+	return nil
+}
+
+// Runs the day on every input, solving at most workers inputs at the same time
+// A failing input, even one making the solver panic, does not stop the others
+// With verify, all the solutions of each part are run and compared
+// Each solution is stopped after timeout when it is not 0, and all of them when ctx is done
+func runAll(ctx context.Context, day aoc.Day, runs []*run, workers int, verify bool, timeout time.Duration) {
+	jobs := make(chan *run)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for r := range jobs {
+				runOne(ctx, day, r, verify, timeout)
+			}
+		}()
+	}
+	for _, r := range runs {
+		jobs <- r
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func runOne(ctx context.Context, day aoc.Day, r *run, verify bool, timeout time.Duration) {
+	defer func() {
+		if p := recover(); p != nil {
+			r.err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	if r.path != "" {
+		if r.data, r.err = os.ReadFile(r.path); r.err != nil {
+			return
+		}
+	}
+	r.result, r.err = aoc.Run(ctx, day, r.data, timeout)
+	if r.err == nil && verify {
+		r.disagreements, r.err = aoc.Verify(ctx, day, r.data, timeout)
+	}
+}
+
+// Compares the answers with the ones recorded for the same input, and records the new ones
+// Returns the answers that changed. They are only recorded when update is true
+func checkAnswers(db *regress.DB, update bool, day int, inputHash string, result aoc.Result) []regress.Mismatch {
+	mismatches := make([]regress.Mismatch, 0)
+	for index, part := range result.Parts {
+		key := regress.Key{Day: day, Part: index + 1, InputHash: inputHash}
+		var mismatch regress.Mismatch
+		var changed bool
+		if interrupted(part.Err) {
+			// Not an answer, the part just did not have the time to find it
+			continue
+		} else if part.Err != nil {
+			mismatch, changed = db.CheckError(key, part.Err)
+		} else {
+			mismatch, changed = db.Check(key, part.Answer.String(), update)
+		}
+		if changed {
+			mismatches = append(mismatches, mismatch)
+		}
+	}
+	return mismatches
+}
+
+// Tells whether the error comes from a solver stopped by --timeout or Ctrl-C
+func interrupted(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+func formatMeasure(m aoc.Measure) string {
+	return fmt.Sprintf("%s, %s allocated", m.Elapsed, formatBytes(m.Allocated))
+}
+
+// Returns a number of bytes in a human readable form, like 12.3 MB
+func formatBytes(b uint64) string {
+	const unit = 1000
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+	div, exp := uint64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(b)/float64(div), "kMGTPE"[exp])
+}
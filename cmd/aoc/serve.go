@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aymec/adventofcode2021/server"
+)
+
+func serveCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", "localhost:8080", "address to listen on")
+	maxSize := flags.Int64("max-input", server.DefaultMaxInputSize, "largest puzzle input accepted, in bytes")
+	timeout := flags.Duration("timeout", 30*time.Second, "stop solving a part after this long (0 means no limit)")
+	args = parseArgs(flags, args)
+	if len(args) != 0 {
+		return errors.New("usage: aoc serve [flags]")
+	}
+
+	s := server.New()
+	s.MaxInputSize = *maxSize
+	s.Timeout = *timeout
+	srv := &http.Server{Addr: *addr, Handler: s, ReadHeaderTimeout: 10 * time.Second}
+
+	// Ctrl-C stops the server, after the requests being solved are answered
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+	log.Printf("Solving on http://%s", *addr)
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/day1"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// The tools for the sonar readings of day 1, beyond the puzzle
+var sonarCommands = []command{
+	{"count", "count FILE [flags]\tcount the increases in a large file of readings, in parallel", sonarCountCmd},
+	{"clean", "clean FILE [flags]\tfind the missing, garbled and wrong readings, and fill or drop them", sonarCleanCmd},
+	{"windows", "windows FILE [flags]\tcount the increases of windows of readings aggregated by median, min, max, mean or ewma", sonarWindowsCmd},
+	{"measure", "measure FILE [flags]\tcount the increases of decimal measurements with units, like 159.25m or 520ft", sonarMeasureCmd},
+	{"watch", "watch [FILE] [flags]\tcount the increases of readings as they arrive, from stdin, a named pipe or TCP", sonarWatchCmd},
+}
+
+func sonarCmd(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		for _, cmd := range sonarCommands {
+			if cmd.name == args[0] {
+				return cmd.run(ctx, args[1:])
+			}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Usage: aoc sonar <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range sonarCommands {
+		fmt.Fprintf(os.Stderr, "\t%s\n", cmd.usage)
+	}
+	return errors.New("unknown sonar command")
+}
+
+// Parses a list of window sizes like 1,3
+func parseWindows(arg string) ([]int, error) {
+	windows, err := input.CommaInts(arg, 1)
+	if err != nil {
+		return nil, fmt.Errorf("invalid windows %q: %w", arg, err)
+	}
+	for _, window := range windows {
+		if window < 1 {
+			return nil, fmt.Errorf("invalid window %d, expected 1 or more", window)
+		}
+	}
+	return windows, nil
+}
+
+func sonarCountCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sonar count", flag.ExitOnError)
+	windowsArg := flags.String("windows", "1,3", "sizes of the windows to compare, 1 is part 1 and 3 is part 2")
+	workers := flags.Int("workers", runtime.NumCPU(), "number of parts of the file read at the same time")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sonar count FILE [flags]")
+	}
+	windows, err := parseWindows(*windowsArg)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	for _, window := range windows {
+		count, err := day1.CountIncreases(ctx, f, info.Size(), window, *workers)
+		if err != nil {
+			return fmt.Errorf("%s: %w", args[0], err)
+		}
+		fmt.Printf("Window %d - %d increases\n", window, count)
+	}
+	return nil
+}
+
+func sonarCleanCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sonar clean", flag.ExitOnError)
+	options := day1.DefaultCleanOptions
+	method := flags.String("method", string(options.Method), "how to find the outliers: mad (median absolute deviation) or zscore")
+	flags.IntVar(&options.Window, "window", options.Window, "number of readings around a reading it is compared with")
+	flags.Float64Var(&options.Threshold, "threshold", options.Threshold, "distance to the readings around, in deviations, beyond which a reading is an outlier")
+	flags.BoolVar(&options.Interpolate, "interpolate", false, "replace the wrong readings with an interpolation of the readings around them, instead of dropping them")
+	output := flags.String("output", "", "write the cleaned readings to this file")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sonar clean FILE [flags]")
+	}
+	options.Method = day1.Method(*method)
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	cleaning, err := day1.Clean(ctx, data, options)
+	if err != nil {
+		return err
+	}
+	for _, reading := range cleaning.Altered() {
+		fix := "dropped"
+		if reading.Filled {
+			fix = fmt.Sprintf("replaced with %d", reading.Depth)
+		}
+		fmt.Printf("Line %d - %s %q, %s\n", reading.Line, reading.Status, reading.Raw, fix)
+	}
+	fmt.Printf("%d of %d readings altered\n", len(cleaning.Altered()), len(cleaning.Readings))
+	for index := range cleaning.Before {
+		before, after := cleaning.Before[index], cleaning.After[index]
+		fmt.Printf("Part %d - %d increases before cleaning, %d after (%+d)\n", index+1, before, after, after-before)
+	}
+
+	if *output != "" {
+		var b strings.Builder
+		for _, depth := range cleaning.Depths {
+			fmt.Fprintln(&b, depth)
+		}
+		return os.WriteFile(*output, []byte(b.String()), 0644)
+	}
+	return nil
+}
+
+func sonarWindowsCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sonar windows", flag.ExitOnError)
+	options := day1.DefaultWindowOptions
+	by := flags.String("by", string(options.Aggregation), fmt.Sprintf("aggregation of the readings of a window, one of %v", day1.Aggregations))
+	flags.IntVar(&options.Size, "size", options.Size, "number of readings of a window")
+	flags.IntVar(&options.Stride, "stride", options.Stride, "number of readings between the starts of two windows, the size for tumbling windows")
+	flags.Float64Var(&options.Alpha, "alpha", options.Alpha, "smoothing factor of ewma, the larger the more the latest reading weighs")
+	values := flags.Bool("values", false, "print the value of every window")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sonar windows FILE [flags]")
+	}
+	options.Aggregation = day1.Aggregation(*by)
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	depths, err := day1.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	windows, err := day1.Windows(ctx, depths, options)
+	if err != nil {
+		return err
+	}
+	if *values {
+		for index, value := range windows {
+			fmt.Printf("Window %d - %g\n", index+1, value)
+		}
+	}
+	count, err := day1.CompareWindows(ctx, depths, options)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d windows of %d readings by %s, %d increases\n", len(windows), options.Size, options.Aggregation, count)
+	return nil
+}
+
+func sonarMeasureCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sonar measure", flag.ExitOnError)
+	windowsArg := flags.String("windows", "1,3", "sizes of the windows to compare, 1 is part 1 and 3 is part 2")
+	unit := flags.String("unit", "m", fmt.Sprintf("unit of the measurements without one, and of the output, one of %v", day1.Units()))
+	epsilonArg := flags.String("epsilon", "0", "smallest difference counted as an increase, like 5cm, in --unit without unit")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sonar measure FILE [flags]")
+	}
+	windows, err := parseWindows(*windowsArg)
+	if err != nil {
+		return err
+	}
+	epsilon, err := day1.ParseMeasurement(*epsilonArg, *unit)
+	if err != nil {
+		return fmt.Errorf("invalid epsilon: %w", err)
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	measurements, err := day1.ParseMeasurements(data, *unit)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	shallowest, deepest := measurements[0], measurements[0]
+	for _, m := range measurements {
+		if m < shallowest {
+			shallowest = m
+		}
+		if m > deepest {
+			deepest = m
+		}
+	}
+	fmt.Printf("%d measurements, from %s to %s\n", len(measurements), shallowest.Format(*unit), deepest.Format(*unit))
+	for _, window := range windows {
+		count, err := day1.CountMeasuredIncreases(ctx, measurements, window, epsilon)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Window %d - %d increases of more than %s\n", window, count, epsilon.Format(*unit))
+	}
+	return nil
+}
+
+func sonarWatchCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sonar watch", flag.ExitOnError)
+	windowsArg := flags.String("windows", "1,3", "sizes of the windows to compare, 1 is part 1 and 3 is part 2")
+	alert := flags.Int("alert", 5, "raise an alert after this many consecutive increases, 0 for no alert")
+	every := flags.Int("every", 1, "print the counts every this many readings")
+	listen := flags.String("listen", "", "read the readings of the sensors connecting to this TCP address, like localhost:9000")
+	args = parseArgs(flags, args)
+	if len(args) > 1 || (len(args) == 1 && *listen != "") {
+		return errors.New("usage: aoc sonar watch [FILE] [flags], FILE can be a named pipe, the readings come from stdin without it")
+	}
+	windows, err := parseWindows(*windowsArg)
+	if err != nil {
+		return err
+	}
+	m, err := day1.NewMonitor(windows, *alert)
+	if err != nil {
+		return err
+	}
+	emit := func(update day1.Update) error {
+		switch {
+		case update.Err != nil:
+			fmt.Fprintln(os.Stderr, update)
+		case update.Alert != "":
+			fmt.Println(update)
+			fmt.Printf("ALERT: %s\n", update.Alert)
+		case *every <= 1 || update.Reading%*every == 0:
+			fmt.Println(update)
+		}
+		return nil
+	}
+
+	switch {
+	case *listen != "":
+		l, listenErr := net.Listen("tcp", *listen)
+		if listenErr != nil {
+			return listenErr
+		}
+		defer l.Close()
+		log.Printf("Waiting for readings on %s", l.Addr())
+		err = m.WatchListener(ctx, l, emit)
+	case len(args) == 1 && args[0] != "-":
+		// Opening a named pipe waits for a writer
+		f, openErr := os.Open(args[0])
+		if openErr != nil {
+			return openErr
+		}
+		defer f.Close()
+		err = m.Watch(ctx, f, emit)
+	default:
+		err = m.Watch(ctx, os.Stdin, emit)
+	}
+	// Ctrl-C is the way to stop watching a source that never ends
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
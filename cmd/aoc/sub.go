@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/day2"
+)
+
+// The tools for the submarine instructions of day 2, beyond the puzzle
+var subCommands = []command{
+	{"script", "script FILE [flags]\tcompile a script with repeat, macros and variables to the instructions of the puzzle", subScriptCmd},
+	{"simulate", "simulate FILE [flags]\tmove the submarine through instructions with a model, like 3d with left and right turns", subSimulateCmd},
+	{"scan", "scan FILE [flags]\tsolve part 2 on a huge log of instructions, in parallel", subScanCmd},
+	{"plan", "plan [flags]\twrite instructions that take the submarine to a target position", subPlanCmd},
+}
+
+func subCmd(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		for _, cmd := range subCommands {
+			if cmd.name == args[0] {
+				return cmd.run(ctx, args[1:])
+			}
+		}
+	}
+	fmt.Fprintln(os.Stderr, "Usage: aoc sub <command> [arguments]")
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range subCommands {
+		fmt.Fprintf(os.Stderr, "\t%s\n", cmd.usage)
+	}
+	return errors.New("unknown sub command")
+}
+
+// Prints the answers of both parts for instructions
+func printSubAnswers(ctx context.Context, instructions []day2.Elements) error {
+	for index, part := range []func(context.Context, []day2.Elements) (aoc.Answer, error){day2.Part1, day2.Part2} {
+		answer, err := part(ctx, instructions)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Part %d - %s\n", index+1, answer)
+	}
+	return nil
+}
+
+func subScriptCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sub script", flag.ExitOnError)
+	output := flags.String("output", "", "write the instructions to this file, for `aoc run 2 --input`")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sub script FILE [flags]")
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	script, err := day2.ParseScript(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	instructions, err := script.Compile(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	fmt.Printf("%d instructions\n", len(instructions))
+	if err := printSubAnswers(ctx, instructions); err != nil {
+		return err
+	}
+
+	if *output != "" {
+		var b strings.Builder
+		for _, instruction := range instructions {
+			fmt.Fprintln(&b, instruction)
+		}
+		return os.WriteFile(*output, []byte(b.String()), 0644)
+	}
+	return nil
+}
+
+func subSimulateCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sub simulate", flag.ExitOnError)
+	model := flags.String("model", string(day2.Model3D), fmt.Sprintf("physics of the submarine, one of %v", day2.Models))
+	depth := flags.String("depth", ":", "allowed depths, like 0:2000 to stay under the surface and above 2000")
+	aim := flags.String("aim", ":", "allowed aims, like -50:50")
+	horizontal := flags.String("horizontal", ":", "allowed horizontal positions, like :5000")
+	mode := flags.String("mode", string(day2.Halt), fmt.Sprintf("what to do out of the limits: %s before the instruction, or %s the values back in", day2.Halt, day2.Clamp))
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sub simulate FILE [flags]")
+	}
+	m := day2.Model(*model)
+	limits := day2.Limits{Mode: day2.Mode(*mode)}
+	for _, limit := range []struct {
+		arg    string
+		bounds *day2.Bounds
+	}{{*depth, &limits.Depth}, {*aim, &limits.Aim}, {*horizontal, &limits.Horizontal}} {
+		var err error
+		if *limit.bounds, err = day2.ParseBounds(limit.arg); err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	instructions, err := m.Parse(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	position, violations, err := m.SimulateWithin(ctx, instructions, limits)
+	var violation *day2.Violation
+	if errors.As(err, &violation) {
+		fmt.Printf("Halted at %s\n", position)
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	for index := range violations {
+		fmt.Printf("Clamped: %s\n", &violations[index])
+	}
+	fmt.Printf("%d instructions to %s\n", len(instructions), position)
+	return nil
+}
+
+func subScanCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sub scan", flag.ExitOnError)
+	workers := flags.Int("workers", runtime.NumCPU(), "number of parts of the file read at the same time")
+	args = parseArgs(flags, args)
+	if len(args) != 1 {
+		return errors.New("usage: aoc sub scan FILE [flags]")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	answer, err := day2.ScanPart2(ctx, f, info.Size(), *workers)
+	if err != nil {
+		return fmt.Errorf("%s: %w", args[0], err)
+	}
+	fmt.Printf("Part 2 - %s\n", answer)
+	return nil
+}
+
+func subPlanCmd(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("sub plan", flag.ExitOnError)
+	horizontal := flags.Int("horizontal", 0, "horizontal position of the target")
+	depth := flags.Int("depth", 0, "depth of the target")
+	aim := flags.Int("aim", 0, "aim of the target, for the aim model. Without it, the plan ends with any aim")
+	model := flags.String("model", string(day2.AimModel), fmt.Sprintf("physics of the submarine, one of %v", day2.Models))
+	var options day2.PlanOptions
+	flags.IntVar(&options.MaxStep, "max-step", 0, "largest value of an instruction, 0 for no limit")
+	flags.BoolVar(&options.AboveSurface, "above-surface", false, "allow the submarine above the surface on the way")
+	flags.IntVar(&options.MaxLength, "max-length", 0, "largest number of instructions, 0 for no limit")
+	output := flags.String("output", "", "write the instructions to this file instead of the standard output")
+	if args = parseArgs(flags, args); len(args) != 0 {
+		return errors.New("usage: aoc sub plan [flags]")
+	}
+	options.Model = day2.Model(*model)
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "aim" {
+			options.MatchAim = true
+		}
+	})
+
+	target := day2.NewPosition(*horizontal, *depth, *aim)
+	plan, err := day2.Plan(target, options)
+	if err != nil {
+		return err
+	}
+	position, err := options.Model.Simulate(ctx, plan)
+	if err != nil {
+		return err
+	}
+	log.Printf("%d instructions to %s", len(plan), position)
+
+	var b strings.Builder
+	for _, instruction := range plan {
+		fmt.Fprintln(&b, instruction)
+	}
+	if *output != "" {
+		return os.WriteFile(*output, []byte(b.String()), 0644)
+	}
+	fmt.Print(b.String())
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// How to play the animation of a day
+type visualizeFlags struct {
+	enabled bool
+	fps     float64
+	step    bool
+}
+
+func (f *visualizeFlags) register(flags *flag.FlagSet) {
+	flags.BoolVar(&f.enabled, "visualize", false, "animate the solving of the puzzle in the terminal before solving it")
+	flags.Float64Var(&f.fps, "fps", 10, "frames per second of the animation, + and - change it while playing")
+	flags.BoolVar(&f.step, "step", false, "start the animation paused, Enter shows the next frame")
+}
+
+// Plays the animation of the day on the input, reading the commands of the user from the standard input
+// Stopping the animation with q is not an error
+func (f *visualizeFlags) play(ctx context.Context, day aoc.Day, data []byte) error {
+	if day.Visualize == nil {
+		return fmt.Errorf("day %d has no animation", day.Number)
+	}
+	puzzle, err := day.Parse(data)
+	if err != nil {
+		return err
+	}
+	// Stop reading the commands once the animation is over
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	player := visual.NewPlayer(ctx, os.Stdout, os.Stdin, f.fps, f.step)
+	err = day.Visualize(ctx, puzzle, player.Show)
+	if errors.Is(err, visual.ErrQuit) {
+		return nil
+	}
+	return err
+}
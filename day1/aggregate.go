@@ -0,0 +1,301 @@
+package day1
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Aggregation is the way the readings of a window are combined into the value
+// the windows are compared with
+type Aggregation string
+
+const (
+	// Sum is the aggregation of Part2
+	Sum    Aggregation = "sum"
+	Mean   Aggregation = "mean"
+	Median Aggregation = "median"
+	Min    Aggregation = "min"
+	Max    Aggregation = "max"
+	// EWMA is the exponentially weighted mean, the latest readings of the window
+	// weigh more than the older ones
+	EWMA Aggregation = "ewma"
+)
+
+// Aggregations are all the aggregations, in the order they are listed to the user
+var Aggregations = []Aggregation{Sum, Mean, Median, Min, Max, EWMA}
+
+// WindowOptions tells how the readings are grouped in windows and how a window
+// is aggregated
+type WindowOptions struct {
+	Aggregation Aggregation
+	// Size is the number of readings of a window
+	Size int
+	// Stride is the number of readings between the starts of two windows:
+	// 1 for overlapping windows as in Part2, Size for tumbling windows
+	Stride int
+	// Alpha is the smoothing factor of EWMA, more than 0 and up to 1
+	// The larger it is, the more the latest reading weighs
+	Alpha float64
+}
+
+// DefaultWindowOptions are the windows of Part2
+var DefaultWindowOptions = WindowOptions{Aggregation: Sum, Size: 3, Stride: 1, Alpha: 0.5}
+
+// Windows returns the aggregated value of each window of the depths, in order
+// A window slides over the depths one reading at a time whatever the stride, so
+// no window is computed from scratch, only the windows on the stride are kept
+func Windows(ctx context.Context, depths []int, options WindowOptions) ([]float64, error) {
+	if options.Size < 1 {
+		return nil, fmt.Errorf("invalid window size %d, expected 1 or more", options.Size)
+	}
+	if options.Stride < 1 {
+		return nil, fmt.Errorf("invalid stride %d, expected 1 or more", options.Stride)
+	}
+	var window aggregator
+	switch options.Aggregation {
+	case Sum:
+		window = &sumWindow{}
+	case Mean:
+		window = &sumWindow{mean: true}
+	case Median:
+		window = newMedianWindow()
+	case Min:
+		window = &dequeWindow{depths: depths, before: func(a, b int) bool { return a < b }}
+	case Max:
+		window = &dequeWindow{depths: depths, before: func(a, b int) bool { return a > b }}
+	case EWMA:
+		if options.Alpha <= 0 || options.Alpha > 1 {
+			return nil, fmt.Errorf("invalid alpha %g, expected more than 0 and up to 1", options.Alpha)
+		}
+		window = newEWMAWindow(options.Size, options.Alpha)
+	default:
+		return nil, fmt.Errorf("invalid aggregation %q, expected one of %v", options.Aggregation, Aggregations)
+	}
+
+	if len(depths) < options.Size {
+		return []float64{}, nil
+	}
+	values := make([]float64, 0, (len(depths)-options.Size)/options.Stride+1)
+	for index, depth := range depths {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return nil, err
+		}
+		if first := index - options.Size; first >= 0 {
+			window.remove(first, depths[first])
+		}
+		window.add(index, depth)
+		if first := index - options.Size + 1; first >= 0 && first%options.Stride == 0 {
+			values = append(values, window.value())
+		}
+	}
+	return values, nil
+}
+
+// CompareWindows counts the windows whose value is larger than the value of
+// the window before them. Sums of 3 readings with a stride of 1 give the answer
+// to Part2, and any aggregation of windows of 1 reading the answer to Part1
+func CompareWindows(ctx context.Context, depths []int, options WindowOptions) (int, error) {
+	values, err := Windows(ctx, depths, options)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for index := 1; index < len(values); index++ {
+		if increased(values[index-1], values[index], options.Aggregation) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// The other aggregations are exact, up to 2^53, but the EWMA of a window is
+// updated with floats and drifts a little: equal windows must not be an increase
+func increased(previous, value float64, aggregation Aggregation) bool {
+	if aggregation == EWMA {
+		return value-previous > 1e-9*math.Max(1, math.Abs(previous))
+	}
+	return value > previous
+}
+
+// A window of readings, the readings enter it and leave it in order
+type aggregator interface {
+	add(index int, depth int)
+	remove(index int, depth int)
+	value() float64
+}
+
+// Sum and mean of the window, kept up to date as in Part2
+type sumWindow struct {
+	sum, size int
+	mean      bool
+}
+
+func (w *sumWindow) add(index int, depth int) {
+	w.sum += depth
+	w.size++
+}
+
+func (w *sumWindow) remove(index int, depth int) {
+	w.sum -= depth
+	w.size--
+}
+
+func (w *sumWindow) value() float64 {
+	if w.mean {
+		return float64(w.sum) / float64(w.size)
+	}
+	return float64(w.sum)
+}
+
+// Min or max of the window, with a monotonic deque: the indexes of the readings
+// that can still be the min (or max) of a window, the first one is the current one
+// A reading removes the ones before it it beats, they can't be the min anymore
+type dequeWindow struct {
+	depths  []int
+	indexes []int
+	// Tells when a is the min (or max) rather than b
+	before func(a, b int) bool
+}
+
+func (w *dequeWindow) add(index int, depth int) {
+	last := len(w.indexes) - 1
+	for last >= 0 && !w.before(w.depths[w.indexes[last]], depth) {
+		last--
+	}
+	w.indexes = append(w.indexes[:last+1], index)
+}
+
+func (w *dequeWindow) remove(index int, depth int) {
+	if len(w.indexes) > 0 && w.indexes[0] == index {
+		w.indexes = w.indexes[1:]
+	}
+}
+
+func (w *dequeWindow) value() float64 {
+	return float64(w.depths[w.indexes[0]])
+}
+
+// Median of the window, with two heaps: the lower half of the readings in a max
+// heap, the upper half in a min heap. The median is at the top of the heaps
+// Removed readings are only dropped when they reach the top of their heap
+type medianWindow struct {
+	lower, upper *intHeap
+	// Number of readings of each half still in the window
+	lowerSize, upperSize int
+	// Removed readings still in the heaps, by value
+	removed map[int]int
+}
+
+func newMedianWindow() *medianWindow {
+	return &medianWindow{
+		lower:   &intHeap{less: func(a, b int) bool { return a > b }},
+		upper:   &intHeap{less: func(a, b int) bool { return a < b }},
+		removed: make(map[int]int),
+	}
+}
+
+func (w *medianWindow) add(index int, depth int) {
+	if w.lowerSize == 0 || depth <= w.lower.top() {
+		heap.Push(w.lower, depth)
+		w.lowerSize++
+	} else {
+		heap.Push(w.upper, depth)
+		w.upperSize++
+	}
+	w.balance()
+}
+
+func (w *medianWindow) remove(index int, depth int) {
+	w.removed[depth]++
+	// The top of lower is valid, a reading up to it is in lower
+	if depth <= w.lower.top() {
+		w.lowerSize--
+		w.prune(w.lower)
+	} else {
+		w.upperSize--
+		w.prune(w.upper)
+	}
+	w.balance()
+}
+
+// Keeps as many readings in lower as in upper, or one more
+func (w *medianWindow) balance() {
+	switch {
+	case w.lowerSize > w.upperSize+1:
+		heap.Push(w.upper, heap.Pop(w.lower))
+		w.lowerSize--
+		w.upperSize++
+		w.prune(w.lower)
+	case w.lowerSize < w.upperSize:
+		heap.Push(w.lower, heap.Pop(w.upper))
+		w.upperSize--
+		w.lowerSize++
+		w.prune(w.upper)
+	}
+}
+
+// Drops the removed readings from the top of a heap
+func (w *medianWindow) prune(h *intHeap) {
+	for h.Len() > 0 && w.removed[h.top()] > 0 {
+		w.removed[h.top()]--
+		heap.Pop(h)
+	}
+}
+
+func (w *medianWindow) value() float64 {
+	if w.lowerSize > w.upperSize {
+		return float64(w.lower.top())
+	}
+	return (float64(w.lower.top()) + float64(w.upper.top())) / 2
+}
+
+type intHeap struct {
+	values []int
+	less   func(a, b int) bool
+}
+
+func (h intHeap) Len() int            { return len(h.values) }
+func (h intHeap) Less(i, j int) bool  { return h.less(h.values[i], h.values[j]) }
+func (h intHeap) Swap(i, j int)       { h.values[i], h.values[j] = h.values[j], h.values[i] }
+func (h *intHeap) Push(v interface{}) { h.values = append(h.values, v.(int)) }
+func (h *intHeap) Pop() interface{} {
+	v := h.values[len(h.values)-1]
+	h.values = h.values[:len(h.values)-1]
+	return v
+}
+func (h intHeap) top() int { return h.values[0] }
+
+// Exponentially weighted mean of the window: the reading k positions before the
+// latest one weighs (1-alpha)^k. The weighted sum is updated as the window
+// slides: the readings already in it weigh 1-alpha times less when one enters
+type ewmaWindow struct {
+	decay float64
+	// Weight of the oldest reading of a full window
+	oldest float64
+	sum    float64
+	// Sum of the weights of the readings in the window
+	weights float64
+}
+
+func newEWMAWindow(size int, alpha float64) *ewmaWindow {
+	return &ewmaWindow{decay: 1 - alpha, oldest: math.Pow(1-alpha, float64(size-1))}
+}
+
+func (w *ewmaWindow) add(index int, depth int) {
+	w.sum = w.sum*w.decay + float64(depth)
+	w.weights = w.weights*w.decay + 1
+}
+
+// Only called on a full window, before the next reading is added
+func (w *ewmaWindow) remove(index int, depth int) {
+	w.sum -= w.oldest * float64(depth)
+	w.weights -= w.oldest
+}
+
+func (w *ewmaWindow) value() float64 {
+	return w.sum / w.weights
+}
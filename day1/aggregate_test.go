@@ -0,0 +1,116 @@
+package day1
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// Aggregates a window from scratch
+func aggregateWindow(window []int, options WindowOptions) float64 {
+	switch options.Aggregation {
+	case Sum, Mean:
+		sum := 0
+		for _, depth := range window {
+			sum += depth
+		}
+		if options.Aggregation == Mean {
+			return float64(sum) / float64(len(window))
+		}
+		return float64(sum)
+	case Median:
+		sorted := append([]int{}, window...)
+		sort.Ints(sorted)
+		return medianSorted(sorted)
+	case Min, Max:
+		sorted := append([]int{}, window...)
+		sort.Ints(sorted)
+		if options.Aggregation == Min {
+			return float64(sorted[0])
+		}
+		return float64(sorted[len(sorted)-1])
+	case EWMA:
+		sum, weights, weight := 0.0, 0.0, 1.0
+		for index := len(window) - 1; index >= 0; index-- {
+			sum += weight * float64(window[index])
+			weights += weight
+			weight *= 1 - options.Alpha
+		}
+		return sum / weights
+	}
+	panic("unknown aggregation")
+}
+
+func TestWindows(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	// Few distinct depths, so the windows have equal readings
+	depths := make([]int, 300)
+	for index := range depths {
+		depths[index] = random.Intn(10)
+	}
+	for _, aggregation := range Aggregations {
+		for _, size := range []int{1, 2, 3, 4, 7} {
+			for _, stride := range []int{1, 2, size, size + 2} {
+				options := WindowOptions{Aggregation: aggregation, Size: size, Stride: stride, Alpha: 0.3}
+				values, err := Windows(context.Background(), depths, options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				expected := make([]float64, 0)
+				for first := 0; first+size <= len(depths); first += stride {
+					expected = append(expected, aggregateWindow(depths[first:first+size], options))
+				}
+				if len(values) != len(expected) {
+					t.Fatalf("%+v: got %d windows, expected %d", options, len(values), len(expected))
+				}
+				for index := range values {
+					if math.Abs(values[index]-expected[index]) > 1e-9 {
+						t.Errorf("%+v: window %d, got %g, expected %g", options, index, values[index], expected[index])
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCompareWindows(t *testing.T) {
+	depths, _ := Parse(Generate(2000))
+	part1, _ := Part1(context.Background(), depths)
+	part2, _ := Part2(context.Background(), depths)
+	for _, aggregation := range Aggregations {
+		options := WindowOptions{Aggregation: aggregation, Size: 1, Stride: 1, Alpha: 0.5}
+		if count, err := CompareWindows(context.Background(), depths, options); err != nil || count != part1 {
+			t.Errorf("%+v: got %d, %v, expected %d", options, count, err, part1)
+		}
+	}
+	if count, err := CompareWindows(context.Background(), depths, DefaultWindowOptions); err != nil || count != part2 {
+		t.Errorf("%+v: got %d, %v, expected %d", DefaultWindowOptions, count, err, part2)
+	}
+
+	// The EWMA of equal windows drifts, but it is not an increase
+	constant := make([]int, 10000)
+	for index := range constant {
+		constant[index] = 7919
+	}
+	options := WindowOptions{Aggregation: EWMA, Size: 5, Stride: 1, Alpha: 0.1}
+	if count, err := CompareWindows(context.Background(), constant, options); err != nil || count != 0 {
+		t.Errorf("constant readings: got %d, %v, expected 0", count, err)
+	}
+}
+
+func TestWindowsErrors(t *testing.T) {
+	for _, options := range []WindowOptions{
+		{Aggregation: Sum, Size: 0, Stride: 1},
+		{Aggregation: Sum, Size: 3, Stride: 0},
+		{Aggregation: "mode", Size: 3, Stride: 1},
+		{Aggregation: EWMA, Size: 3, Stride: 1, Alpha: 0},
+		{Aggregation: EWMA, Size: 3, Stride: 1, Alpha: 1.5},
+	} {
+		if _, err := Windows(context.Background(), []int{1, 2, 3}, options); err == nil {
+			t.Errorf("%+v: expected an error", options)
+		}
+	}
+}
@@ -0,0 +1,313 @@
+package day1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// Status tells what the cleaning found wrong with a reading
+type Status int
+
+const (
+	// Valid readings are kept as they are
+	Valid Status = iota
+	// Missing readings are empty lines, the sonar dropped them
+	Missing
+	// Garbled readings are lines that are not integers
+	Garbled
+	// Outliers are integers too far from the readings around them to be true
+	Outlier
+)
+
+func (s Status) String() string {
+	switch s {
+	case Valid:
+		return "valid"
+	case Missing:
+		return "missing"
+	case Garbled:
+		return "garbled"
+	case Outlier:
+		return "outlier"
+	}
+	return fmt.Sprintf("Status(%d)", int(s))
+}
+
+// Method is the way outliers are detected
+type Method string
+
+const (
+	// ZScore flags the readings more than Threshold standard deviations away
+	// from the mean of the readings around them
+	ZScore Method = "zscore"
+	// MAD flags the readings more than Threshold median absolute deviations
+	// away from the median of the readings around them. Unlike the mean and
+	// the standard deviation, the median is not moved by the outliers themselves
+	MAD Method = "mad"
+)
+
+// CleanOptions tells how to detect and fix the wrong readings
+type CleanOptions struct {
+	Method Method
+	// Window is the number of readings around a reading it is compared with,
+	// half before it and half after it
+	Window    int
+	Threshold float64
+	// Interpolate replaces the wrong readings with a linear interpolation of the
+	// valid readings around them. Without it, they are dropped
+	Interpolate bool
+}
+
+// DefaultCleanOptions finds no outlier in the real input, the sonar glitches
+// it is made for are much larger than the variations of the depth
+var DefaultCleanOptions = CleanOptions{Method: MAD, Window: 20, Threshold: 5}
+
+// Reading is a line of the input after cleaning
+type Reading struct {
+	// Line number in the input
+	Line int
+	Raw  string
+	// Depth is the depth read, or the interpolated one when the reading was
+	// not valid and Filled is true
+	Depth  int
+	Status Status
+	Filled bool
+}
+
+// Cleaning is what the cleaning did to an input
+type Cleaning struct {
+	Readings []Reading
+	// Depths are the depths after cleaning, to give to the parts
+	Depths []int
+	// Answers to the parts before cleaning, with the integers of the input,
+	// outliers included, and after cleaning
+	Before, After [2]int
+}
+
+// Altered returns the readings that were not valid, filled or dropped
+func (c Cleaning) Altered() []Reading {
+	altered := make([]Reading, 0)
+	for _, reading := range c.Readings {
+		if reading.Status != Valid {
+			altered = append(altered, reading)
+		}
+	}
+	return altered
+}
+
+// Clean reads an input of depths that may have missing and garbled readings,
+// flags the outliers, then fills or drops all of them
+// Unlike Parse, it does not stop at the first wrong line
+func Clean(ctx context.Context, data []byte, options CleanOptions) (Cleaning, error) {
+	if options.Window < 2 {
+		return Cleaning{}, fmt.Errorf("invalid window %d, expected 2 or more", options.Window)
+	}
+	if options.Threshold <= 0 {
+		return Cleaning{}, fmt.Errorf("invalid threshold %g, expected more than 0", options.Threshold)
+	}
+	var deviation func(value int, around []int) float64
+	switch options.Method {
+	case ZScore:
+		deviation = zScore
+	case MAD:
+		deviation = madScore
+	default:
+		return Cleaning{}, fmt.Errorf("invalid method %q, expected %s or %s", options.Method, ZScore, MAD)
+	}
+
+	var cleaning Cleaning
+	lines := input.Lines(data)
+	cleaning.Readings = make([]Reading, len(lines))
+	raw := make([]int, 0, len(lines))
+	for index, line := range lines {
+		reading := Reading{Line: index + 1, Raw: line}
+		var err error
+		switch reading.Depth, err = strconv.Atoi(line); {
+		case line == "":
+			reading.Status, reading.Depth = Missing, 0
+		case err != nil:
+			reading.Status, reading.Depth = Garbled, 0
+		default:
+			raw = append(raw, reading.Depth)
+		}
+		cleaning.Readings[index] = reading
+	}
+
+	// Integers only, the outliers are compared with the readings around them
+	integers := make([]int, 0, len(raw))
+	for index, reading := range cleaning.Readings {
+		if reading.Status == Valid {
+			integers = append(integers, index)
+		}
+	}
+	// The integers of the window around a reading, the reading included, kept
+	// sorted as the window slides: the bounds of the window only move forward
+	window := make([]int, 0, options.Window+1)
+	first, last := 0, -1
+	for k, index := range integers {
+		// Each deviation goes through the whole window, check every time
+		if err := ctx.Err(); err != nil {
+			return Cleaning{}, err
+		}
+		nextFirst := k - options.Window/2
+		if nextFirst < 0 {
+			nextFirst = 0
+		}
+		nextLast := nextFirst + options.Window
+		if nextLast >= len(integers) {
+			nextLast = len(integers) - 1
+			if nextFirst = nextLast - options.Window; nextFirst < 0 {
+				nextFirst = 0
+			}
+		}
+		for ; first < nextFirst; first++ {
+			window = removeSorted(window, cleaning.Readings[integers[first]].Depth)
+		}
+		for last < nextLast {
+			last++
+			window = insertSorted(window, cleaning.Readings[integers[last]].Depth)
+		}
+
+		// The reading is compared with the others
+		depth := cleaning.Readings[index].Depth
+		around := removeSorted(window, depth)
+		if len(around) >= 2 && deviation(depth, around) > options.Threshold {
+			cleaning.Readings[index].Status = Outlier
+		}
+		window = insertSorted(around, depth)
+	}
+
+	if options.Interpolate {
+		fill(cleaning.Readings)
+	}
+	cleaning.Depths = make([]int, 0, len(cleaning.Readings))
+	for _, reading := range cleaning.Readings {
+		if reading.Status == Valid || reading.Filled {
+			cleaning.Depths = append(cleaning.Depths, reading.Depth)
+		}
+	}
+
+	for index, part := range []func(context.Context, []int) (int, error){Part1, Part2} {
+		var err error
+		if cleaning.Before[index], err = part(ctx, raw); err != nil {
+			return Cleaning{}, err
+		}
+		if cleaning.After[index], err = part(ctx, cleaning.Depths); err != nil {
+			return Cleaning{}, err
+		}
+	}
+	return cleaning, nil
+}
+
+// Replaces the depth of the readings that are not valid with a linear
+// interpolation of the valid readings before and after them. Readings at the
+// start or at the end get the depth of the closest valid reading
+func fill(readings []Reading) {
+	previous := -1
+	for index := 0; index <= len(readings); index++ {
+		if index < len(readings) && readings[index].Status != Valid {
+			continue
+		}
+		// The readings between previous and index are not valid
+		if previous < 0 && index == len(readings) {
+			// No valid reading at all, nothing to interpolate from
+			return
+		}
+		for k := previous + 1; k < index; k++ {
+			switch {
+			case previous < 0:
+				readings[k].Depth = readings[index].Depth
+			case index == len(readings):
+				readings[k].Depth = readings[previous].Depth
+			default:
+				from, to := readings[previous].Depth, readings[index].Depth
+				readings[k].Depth = from + int(math.Round(float64((to-from)*(k-previous))/float64(index-previous)))
+			}
+			readings[k].Filled = true
+		}
+		previous = index
+	}
+}
+
+// Inserts a value in sorted values
+func insertSorted(values []int, value int) []int {
+	at := sort.SearchInts(values, value)
+	values = append(values, 0)
+	copy(values[at+1:], values[at:])
+	values[at] = value
+	return values
+}
+
+// Removes a value from sorted values, it must be there
+func removeSorted(values []int, value int) []int {
+	at := sort.SearchInts(values, value)
+	copy(values[at:], values[at+1:])
+	return values[:len(values)-1]
+}
+
+// The deviations take the readings around sorted
+func zScore(value int, around []int) float64 {
+	mean := 0.0
+	for _, v := range around {
+		mean += float64(v)
+	}
+	mean /= float64(len(around))
+	variance := 0.0
+	for _, v := range around {
+		variance += (float64(v) - mean) * (float64(v) - mean)
+	}
+	std := math.Sqrt(variance / float64(len(around)))
+	return score(math.Abs(float64(value)-mean), std)
+}
+
+func madScore(value int, around []int) float64 {
+	m := medianSorted(around)
+	// The deviations from the median grow going away from it on both sides, so
+	// they come in order merging both sides, up to the middle one
+	right := sort.Search(len(around), func(i int) bool { return float64(around[i]) >= m })
+	left := right - 1
+	middle := len(around) / 2
+	var previous, deviation float64
+	for count := 0; count <= middle; count++ {
+		previous = deviation
+		if left < 0 || (right < len(around) && float64(around[right])-m <= m-float64(around[left])) {
+			deviation = float64(around[right]) - m
+			right++
+		} else {
+			deviation = m - float64(around[left])
+			left--
+		}
+	}
+	mad := deviation
+	if len(around)%2 == 0 {
+		mad = (previous + deviation) / 2
+	}
+	// 1.4826 makes the MAD comparable to a standard deviation for normal data
+	return score(math.Abs(float64(value)-m), 1.4826*mad)
+}
+
+// Returns how many spreads away a distance is. Readings around that are all
+// the same have no spread, then any distance is infinite
+func score(distance float64, spread float64) float64 {
+	if spread == 0 {
+		if distance == 0 {
+			return 0
+		}
+		return math.Inf(1)
+	}
+	return distance / spread
+}
+
+// Median of sorted values
+func medianSorted(values []int) float64 {
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (float64(values[middle-1]) + float64(values[middle])) / 2
+	}
+	return float64(values[middle])
+}
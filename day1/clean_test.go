@@ -0,0 +1,152 @@
+package day1
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClean(t *testing.T) {
+	// A missing reading, a garbled one and a glitch in a valid input
+	lines := strings.Split(string(Generate(200)), "\n")
+	depths, _ := Parse(Generate(200))
+	lines[49], lines[99], lines[149] = "", "12x", "99999"
+	data := []byte(strings.Join(lines, "\n"))
+	expectedAltered := map[int]Status{50: Missing, 100: Garbled, 150: Outlier}
+
+	// The integers of the input, glitch included
+	raw := make([]int, 0, len(depths))
+	for index, depth := range depths {
+		switch index {
+		case 49, 99:
+		case 149:
+			raw = append(raw, 99999)
+		default:
+			raw = append(raw, depth)
+		}
+	}
+	// The wrong readings dropped, or replaced with the middle of their neighbours
+	dropped := append(append(append(append([]int{}, depths[:49]...), depths[50:99]...), depths[100:149]...), depths[150:]...)
+	filled := append([]int{}, depths...)
+	for _, index := range []int{49, 99, 149} {
+		filled[index] = depths[index-1] + int(math.Round(float64(depths[index+1]-depths[index-1])/2))
+	}
+
+	for _, interpolate := range []bool{false, true} {
+		options := DefaultCleanOptions
+		options.Interpolate = interpolate
+		cleaning, err := Clean(context.Background(), data, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		altered := make(map[int]Status)
+		for _, reading := range cleaning.Altered() {
+			altered[reading.Line] = reading.Status
+			if reading.Filled != interpolate {
+				t.Errorf("interpolate %t: line %d filled: %t", interpolate, reading.Line, reading.Filled)
+			}
+		}
+		if !reflect.DeepEqual(altered, expectedAltered) {
+			t.Errorf("interpolate %t: got altered readings %v, expected %v", interpolate, altered, expectedAltered)
+		}
+		expected := dropped
+		if interpolate {
+			expected = filled
+		}
+		if !reflect.DeepEqual(cleaning.Depths, expected) {
+			t.Errorf("interpolate %t: got depths %v, expected %v", interpolate, cleaning.Depths, expected)
+		}
+		if before := [2]int{countSequential(raw, 1), countSequential(raw, 3)}; cleaning.Before != before {
+			t.Errorf("interpolate %t: got %v before cleaning, expected %v", interpolate, cleaning.Before, before)
+		}
+		if after := [2]int{countSequential(expected, 1), countSequential(expected, 3)}; cleaning.After != after {
+			t.Errorf("interpolate %t: got %v after cleaning, expected %v", interpolate, cleaning.After, after)
+		}
+	}
+}
+
+func TestCleanValidInput(t *testing.T) {
+	data := Generate(5000)
+	depths, _ := Parse(data)
+	for _, method := range []Method{MAD, ZScore} {
+		options := DefaultCleanOptions
+		options.Method = method
+		cleaning, err := Clean(context.Background(), data, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if altered := cleaning.Altered(); len(altered) != 0 {
+			t.Errorf("%s: %d readings altered, the first one is %+v", method, len(altered), altered[0])
+		}
+		if !reflect.DeepEqual(cleaning.Depths, depths) || cleaning.Before != cleaning.After {
+			t.Errorf("%s: cleaning changed a valid input", method)
+		}
+	}
+}
+
+func TestFillEdges(t *testing.T) {
+	cleaning, err := Clean(context.Background(), []byte("x\n\n10\n11\n12\n13\n\n?"), CleanOptions{Method: ZScore, Window: 4, Threshold: 5, Interpolate: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []int{10, 10, 10, 11, 12, 13, 13, 13}; !reflect.DeepEqual(cleaning.Depths, expected) {
+		t.Errorf("got depths %v, expected %v", cleaning.Depths, expected)
+	}
+}
+
+func TestCleanOptions(t *testing.T) {
+	for _, options := range []CleanOptions{
+		{Method: "mean", Window: 10, Threshold: 3},
+		{Method: MAD, Window: 1, Threshold: 3},
+		{Method: MAD, Window: 10, Threshold: 0},
+	} {
+		if _, err := Clean(context.Background(), []byte("1\n2\n"), options); err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Errorf("%+v: got error %v", options, err)
+		}
+	}
+}
+
+// The MAD of the cleaning merges the deviations of a sorted window, the same
+// as sorting them
+func TestMADScore(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for size := 2; size < 40; size++ {
+		around := make([]int, size)
+		for index := range around {
+			around[index] = r.Intn(20)
+		}
+		sort.Ints(around)
+		m := medianSorted(around)
+		deviations := make([]float64, size)
+		for index, v := range around {
+			deviations[index] = math.Abs(float64(v) - m)
+		}
+		sort.Float64s(deviations)
+		mad := deviations[size/2]
+		if size%2 == 0 {
+			mad = (deviations[size/2-1] + deviations[size/2]) / 2
+		}
+		for _, value := range []int{-5, 0, 10, 30} {
+			expected := score(math.Abs(float64(value)-m), 1.4826*mad)
+			if got := madScore(value, around); got != expected && !(math.IsInf(got, 1) && math.IsInf(expected, 1)) {
+				t.Errorf("%d around %v: got %g, expected %g", value, around, got, expected)
+			}
+		}
+	}
+}
+
+// A large window makes the outlier detection the slow part of the cleaning
+func TestCleanDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := Clean(ctx, Generate(100000), CleanOptions{Method: MAD, Window: 50000, Threshold: 3})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, expected %v", err, context.DeadlineExceeded)
+	}
+}
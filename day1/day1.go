@@ -0,0 +1,137 @@
+package day1
+
+import (
+	"context"
+	"embed"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+//go:embed *.txt
+var files embed.FS
+
+func init() {
+	aoc.Register(aoc.Day{
+		Number: 1,
+		Parse:  func(data []byte) (interface{}, error) { return Parse(data) },
+		Part1: []aoc.Solution{
+			{Name: "sequential", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(Part1(ctx, p.([]int)))
+			}},
+		},
+		Part2: []aoc.Solution{
+			{Name: "sliding-window", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(Part2(ctx, p.([]int)))
+			}},
+			{Name: "window-sums", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(part2WindowSums(ctx, p.([]int)))
+			}},
+			{Name: "compare-ends", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(part2CompareEnds(ctx, p.([]int)))
+			}},
+		},
+		Generate: Generate,
+		Visualize: func(ctx context.Context, p interface{}, show func(string) error) error {
+			return Visualize(ctx, p.([]int), show)
+		},
+		Files: files,
+	})
+}
+
+// Parse reads the input file. It contains a list of integers representing depth measures
+// in the order they are made
+func Parse(data []byte) ([]int, error) {
+	return input.Ints(data)
+}
+
+// Part 1: Count the number of times a depth measurement increases
+func Part1(ctx context.Context, depths []int) (int, error) {
+	if len(depths) == 0 {
+		return 0, nil
+	}
+	// Read the first value, we need to start the comparison somewhere
+	previous := depths[0]
+	count := 0
+
+	for index, value := range depths {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return 0, err
+		}
+		// The first comparison is useless, at least I can use `range`
+		if value > previous {
+			count++
+		}
+		previous = value
+	}
+
+	return count, nil
+}
+
+// Part 2: Make triplets of measures, as a sliding window, and count the number of times
+// the sun of measurements increases over the previous one
+func Part2(ctx context.Context, depths []int) (int, error) {
+	if len(depths) == 0 {
+		return 0, nil
+	}
+	previous := depths[0]
+	count := 0
+	// Value of the current window
+	window := 0
+	// Keep the value at n-3 so it can be removed and the new one add
+	// to reduce the number of additions
+	toRemove := previous
+
+	for index, value := range depths {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return 0, err
+		}
+		previous = window
+		window += value
+
+		if index >= 3 {
+			// Remove the value at index-3 from the window
+			window -= toRemove
+			// Keep the value to remove next
+			toRemove = depths[index-2]
+			if window > previous {
+				count++
+			}
+		}
+	}
+
+	return count, nil
+}
+
+// Same as Part2, but the sum of each window is computed from scratch
+// That's the naive version, kept to measure what the `toRemove` trick is worth
+func part2WindowSums(ctx context.Context, depths []int) (int, error) {
+	count := 0
+	for index := 3; index < len(depths); index++ {
+		if err := aoc.Canceled(ctx, index-3); err != nil {
+			return 0, err
+		}
+		previous := depths[index-3] + depths[index-2] + depths[index-1]
+		window := depths[index-2] + depths[index-1] + depths[index]
+		if window > previous {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// Two consecutive windows share 2 of their 3 measures, so comparing the
+// windows is the same as comparing the measure that enters the window with
+// the one that leaves it. No sum needed at all
+func part2CompareEnds(ctx context.Context, depths []int) (int, error) {
+	count := 0
+	for index := 3; index < len(depths); index++ {
+		if err := aoc.Canceled(ctx, index-3); err != nil {
+			return 0, err
+		}
+		if depths[index] > depths[index-3] {
+			count++
+		}
+	}
+	return count, nil
+}
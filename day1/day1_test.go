@@ -0,0 +1,62 @@
+package day1
+
+import (
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc/aoctest"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// The examples given in the puzzle, with their answers
+var examples = []aoctest.Example{
+	{File: "example.txt", Part1: 7, Part2: 5},
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.CheckExamples(t, 1, examples)
+}
+
+func TestSolutionsAgree(t *testing.T) {
+	aoctest.CheckSolutions(t, 1, Generate(1000))
+}
+
+func TestCanceled(t *testing.T) {
+	aoctest.CheckCanceled(t, 1, Generate(1000))
+}
+
+func TestVisualize(t *testing.T) {
+	aoctest.CheckVisualize(t, 1, Generate(100))
+}
+
+// Number of depth measures in the synthetic input used by the benchmarks
+// It is well above the size of the real input
+const benchSize = 100000
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, 1, Generate(benchSize))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, 1, 1, Generate(benchSize))
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, 1, 2, Generate(benchSize))
+}
+
+func FuzzParse(f *testing.F) {
+	for _, example := range examples {
+		data, err := files.ReadFile(example.File)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(Generate(10))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		depths, err := Parse(data)
+		if err == nil && len(depths) != len(input.Lines(data)) {
+			t.Errorf("got %d depths for %d lines", len(depths), len(input.Lines(data)))
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package day1
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Generate returns a synthetic input of n depth measures
+// The depth follows a random walk, mostly going down, like in the real inputs
+func Generate(n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	data := make([]byte, 0, n*5)
+	depth := 100 + r.Intn(100)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			data = append(data, '\n')
+		}
+		depth += r.Intn(21) - 7
+		if depth < 0 {
+			depth = -depth
+		}
+		data = strconv.AppendInt(data, int64(depth), 10)
+	}
+	return data
+}
@@ -0,0 +1,162 @@
+package day1
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// Measurement is a depth read by a real instrument, like `159.25` or `520ft`,
+// in micrometers. It is fixed-point: the decimals are exact, and sums of
+// measurements don't drift as floats do
+type Measurement int64
+
+// Micrometers in a unit
+var units = map[string]Measurement{
+	"mm":     1000,
+	"cm":     10000,
+	"m":      1000000,
+	"km":     1000000000,
+	"in":     25400,
+	"ft":     304800,
+	"yd":     914400,
+	"fathom": 1828800,
+}
+
+// Units returns the names of the units a measurement can be in, sorted
+func Units() []string {
+	names := make([]string, 0, len(units))
+	for name := range units {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseMeasurement reads a decimal number followed by an optional unit, like
+// `159`, `-3.5m` or `520 ft`. A number without unit is in defaultUnit
+// Decimals finer than a micrometer are rounded
+func ParseMeasurement(s string, defaultUnit string) (Measurement, error) {
+	m, _, err := parseMeasurement(s, defaultUnit)
+	return m, err
+}
+
+// Also returns the column of the problem, for ParseMeasurements
+func parseMeasurement(s string, defaultUnit string) (Measurement, int, error) {
+	// The number is a sign, digits and an optional fraction, big.Rat would
+	// accept more than that, like `1e3` or `1/2`
+	end := 0
+	if end < len(s) && (s[end] == '-' || s[end] == '+') {
+		end++
+	}
+	digits := 0
+	for ; end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] == '.'); end++ {
+		if s[end] != '.' {
+			digits++
+		}
+	}
+	number := s[:end]
+	if digits == 0 || strings.Count(number, ".") > 1 || strings.HasSuffix(number, ".") {
+		return 0, 1, fmt.Errorf("invalid number %q", s)
+	}
+	unit := strings.TrimLeft(s[end:], " ")
+	col := len(s) - len(unit) + 1
+	unit = strings.TrimRight(unit, " ")
+	if unit == "" {
+		unit = defaultUnit
+	}
+	factor, ok := units[unit]
+	if !ok {
+		return 0, col, fmt.Errorf("unknown unit %q, expected one of %v", unit, Units())
+	}
+
+	value, _ := new(big.Rat).SetString(number)
+	value.Mul(value, new(big.Rat).SetInt64(int64(factor)))
+	// Rounded half away from zero
+	quotient, remainder := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int))
+	if remainder.Lsh(remainder.Abs(remainder), 1).Cmp(value.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(value.Sign())))
+	}
+	if !quotient.IsInt64() {
+		return 0, 1, fmt.Errorf("measurement %q out of range", s)
+	}
+	return Measurement(quotient.Int64()), 0, nil
+}
+
+// ParseMeasurements reads an input with one measurement per line, the numbers
+// without unit are in defaultUnit
+func ParseMeasurements(data []byte, defaultUnit string) ([]Measurement, error) {
+	if _, ok := units[defaultUnit]; !ok {
+		return nil, fmt.Errorf("unknown unit %q, expected one of %v", defaultUnit, Units())
+	}
+	lines := input.Lines(data)
+	measurements := make([]Measurement, len(lines))
+	for index, line := range lines {
+		m, col, err := parseMeasurement(line, defaultUnit)
+		if err != nil {
+			return nil, &aoc.ParseError{Line: index + 1, Col: col, Msg: "invalid measurement", Err: err}
+		}
+		measurements[index] = m
+	}
+	return measurements, nil
+}
+
+// In returns the measurement in a unit, it panics on an unknown unit
+func (m Measurement) In(unit string) float64 {
+	factor, ok := units[unit]
+	if !ok {
+		panic(fmt.Sprintf("unknown unit %q", unit))
+	}
+	return float64(m) / float64(factor)
+}
+
+// Format writes the measurement in a unit, with as many decimals as it needs
+// to be exact, up to 6
+func (m Measurement) Format(unit string) string {
+	value := m.In(unit)
+	for decimals := 0; decimals < 6; decimals++ {
+		s := fmt.Sprintf("%.*f", decimals, value)
+		if back, _, err := parseMeasurement(s, unit); err == nil && back == m {
+			return s + unit
+		}
+	}
+	return fmt.Sprintf("%.6f%s", value, unit)
+}
+
+// String writes the measurement in meters
+func (m Measurement) String() string {
+	return m.Format("m")
+}
+
+// CountMeasuredIncreases counts the windows of measurements whose sum is larger
+// than the sum of the window before them by more than epsilon, so the noise of
+// the instrument is not counted as increases
+// As in part2CompareEnds, the windows are compared by the measurements that
+// enter and leave them. Window 1 is Part1, window 3 Part2
+func CountMeasuredIncreases(ctx context.Context, measurements []Measurement, window int, epsilon Measurement) (int, error) {
+	if window < 1 {
+		return 0, fmt.Errorf("invalid window %d, expected 1 or more", window)
+	}
+	if epsilon < 0 {
+		return 0, fmt.Errorf("invalid epsilon %s, expected 0 or more", epsilon)
+	}
+	count := 0
+	for index := window; index < len(measurements); index++ {
+		if err := aoc.Canceled(ctx, index-window); err != nil {
+			return 0, err
+		}
+		// The difference of measurements far apart can overflow, the
+		// measurement that leaves plus epsilon only overflows when no
+		// measurement can be larger
+		if previous := measurements[index-window]; previous <= math.MaxInt64-epsilon && measurements[index] > previous+epsilon {
+			count++
+		}
+	}
+	return count, nil
+}
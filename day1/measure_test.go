@@ -0,0 +1,123 @@
+package day1
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+func TestParseMeasurement(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected Measurement
+	}{
+		{"159", 159000000},
+		{"159.25", 159250000},
+		{"159m", 159000000},
+		{"-3.5 m", -3500000},
+		{"+0.000001", 1},
+		{"0.0000004", 0},
+		{"0.0000005", 1},
+		{"-0.0000005", -1},
+		{"520ft", 158496000},
+		{"1.5 fathom", 2743200},
+		{"12in ", 304800},
+	} {
+		m, err := ParseMeasurement(test.s, "m")
+		if err != nil || m != test.expected {
+			t.Errorf("%q: got %d, %v, expected %d", test.s, m, err, test.expected)
+		}
+	}
+	for _, s := range []string{"", "m", "-", "1.", "1.2.3", "1e3", "1/2", "12 leagues", "99999999999999"} {
+		if m, err := ParseMeasurement(s, "m"); err == nil {
+			t.Errorf("%q: got %d, expected an error", s, m)
+		}
+	}
+}
+
+func TestParseMeasurements(t *testing.T) {
+	measurements, err := ParseMeasurements([]byte("100\n100.5m\n330ft\r\n"), "m")
+	expected := []Measurement{100000000, 100500000, 100584000}
+	if err != nil || len(measurements) != len(expected) {
+		t.Fatalf("got %v, %v, expected %v", measurements, err, expected)
+	}
+	for index := range expected {
+		if measurements[index] != expected[index] {
+			t.Errorf("line %d: got %s, expected %s", index+1, measurements[index], expected[index])
+		}
+	}
+
+	_, err = ParseMeasurements([]byte("100\n100 leagues\n"), "m")
+	var parseErr *aoc.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Col != 5 {
+		t.Errorf("got %v, expected an error at line 2, column 5", err)
+	}
+	if _, err := ParseMeasurements([]byte("100\n"), "leagues"); err == nil {
+		t.Error("expected an error for an unknown default unit")
+	}
+}
+
+func TestMeasurementFormat(t *testing.T) {
+	for _, test := range []struct {
+		m        Measurement
+		unit     string
+		expected string
+	}{
+		{159250000, "m", "159.25m"},
+		{-3500000, "m", "-3.5m"},
+		{158496000, "ft", "520ft"},
+		{1, "m", "0.000001m"},
+		{1, "ft", "0.000003ft"},
+	} {
+		if s := test.m.Format(test.unit); s != test.expected {
+			t.Errorf("%d in %s: got %q, expected %q", test.m, test.unit, s, test.expected)
+		}
+	}
+}
+
+func TestCountMeasuredIncreases(t *testing.T) {
+	// Integers are the same as the puzzle
+	depths, _ := Parse(Generate(2000))
+	measurements := make([]Measurement, len(depths))
+	for index, depth := range depths {
+		measurements[index] = Measurement(depth) * units["m"]
+	}
+	for _, window := range []int{1, 3} {
+		count, err := CountMeasuredIncreases(context.Background(), measurements, window, 0)
+		if expected := countSequential(depths, window); err != nil || count != expected {
+			t.Errorf("window %d: got %d, %v, expected %d", window, count, err, expected)
+		}
+	}
+
+	// The same depth in meters and in feet, then noise
+	measurements, _ = ParseMeasurements([]byte("100m\n328.084ft\n100.01\n100.2\n"), "m")
+	epsilon, _ := ParseMeasurement("5cm", "m")
+	for _, test := range []struct {
+		epsilon  Measurement
+		expected int
+	}{{0, 3}, {epsilon, 1}} {
+		if count, err := CountMeasuredIncreases(context.Background(), measurements, 1, test.epsilon); err != nil || count != test.expected {
+			t.Errorf("epsilon %s: got %d, %v, expected %d", test.epsilon, count, err, test.expected)
+		}
+	}
+
+	// Measurements too far apart for their difference to be a measurement
+	for _, test := range []struct {
+		measurements []Measurement
+		epsilon      Measurement
+		expected     int
+	}{
+		{[]Measurement{math.MaxInt64, math.MinInt64}, 0, 0},
+		{[]Measurement{math.MinInt64, math.MaxInt64}, 0, 1},
+		{[]Measurement{math.MinInt64, math.MaxInt64}, math.MaxInt64, 1},
+		{[]Measurement{0, math.MaxInt64}, math.MaxInt64, 0},
+		{[]Measurement{1, math.MaxInt64}, math.MaxInt64, 0},
+	} {
+		if count, err := CountMeasuredIncreases(context.Background(), test.measurements, 1, test.epsilon); err != nil || count != test.expected {
+			t.Errorf("%v, epsilon %d: got %d, %v, expected %d", test.measurements, int64(test.epsilon), count, err, test.expected)
+		}
+	}
+}
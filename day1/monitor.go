@@ -0,0 +1,241 @@
+package day1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Monitor keeps the counts of increases of a series of readings that arrive
+// one at a time, for several window sizes, without keeping the whole series
+// Window 1 is the count of Part1, window 3 the count of Part2
+type Monitor struct {
+	windows []int
+	counts  []int
+	// The last readings, as many as the largest window, in a ring
+	last     []int
+	readings int
+	// AlertAfter is the number of consecutive increases that raise an alert,
+	// 0 for no alert
+	AlertAfter int
+	// Consecutive increases of window 1 up to the last reading
+	streak int
+}
+
+// Update is what changed with a new reading
+type Update struct {
+	// Number of the reading in the series, starting at 1
+	Reading int
+	Depth   int
+	// Counts of increases for each window, in the order of the windows of the monitor
+	Counts []WindowCount
+	// Alert is set when the reading makes AlertAfter consecutive increases
+	Alert string
+	// Err is set for a line that is not a reading, the series goes on without it
+	Err error
+}
+
+// WindowCount is the number of increases over windows of a size
+type WindowCount struct {
+	Window int
+	Count  int
+}
+
+func (u Update) String() string {
+	if u.Err != nil {
+		return fmt.Sprintf("reading %d: %s", u.Reading, u.Err)
+	}
+	counts := make([]string, len(u.Counts))
+	for index, count := range u.Counts {
+		counts[index] = fmt.Sprintf("window %d: %d", count.Window, count.Count)
+	}
+	return fmt.Sprintf("reading %d: depth %d, increases %s", u.Reading, u.Depth, strings.Join(counts, ", "))
+}
+
+// NewMonitor returns a monitor counting the increases for the given window sizes
+func NewMonitor(windows []int, alertAfter int) (*Monitor, error) {
+	// The streak of increases needs the previous reading, whatever the windows
+	largest := 1
+	for _, window := range windows {
+		if window < 1 {
+			return nil, fmt.Errorf("invalid window %d, expected 1 or more", window)
+		}
+		if window > largest {
+			largest = window
+		}
+	}
+	return &Monitor{
+		windows:    append([]int(nil), windows...),
+		counts:     make([]int, len(windows)),
+		last:       make([]int, largest),
+		AlertAfter: alertAfter,
+	}, nil
+}
+
+// Add counts a new reading
+func (m *Monitor) Add(depth int) Update {
+	update := Update{Reading: m.readings + 1, Depth: depth, Counts: make([]WindowCount, len(m.windows))}
+	for index, window := range m.windows {
+		// The reading window positions before is still in the ring
+		if m.readings >= window && depth > m.last[(m.readings-window)%len(m.last)] {
+			m.counts[index]++
+		}
+		update.Counts[index] = WindowCount{window, m.counts[index]}
+	}
+	if m.readings > 0 && depth > m.last[(m.readings-1)%len(m.last)] {
+		m.streak++
+	} else {
+		m.streak = 0
+	}
+	m.last[m.readings%len(m.last)] = depth
+	m.readings++
+	if m.AlertAfter > 0 && m.streak == m.AlertAfter {
+		update.Alert = fmt.Sprintf("%d consecutive increases, from reading %d to %d", m.streak, m.readings-m.streak, m.readings)
+	}
+	return update
+}
+
+// Lines longer than that are not readings, they are skipped without reading
+// them whole
+const maxWatchLine = 1 << 16
+
+// Watch adds the readings of r to the monitor as they arrive, one per line,
+// and calls emit with every update
+// A line that is not a reading gives an update with an error, the series goes
+// on without it. Watch returns when r is over, when emit fails or when ctx is done
+func (m *Monitor) Watch(ctx context.Context, r io.Reader, emit func(Update) error) error {
+	return m.watch(ctx, "", r, emit)
+}
+
+// Same as Watch, the errors of the lines name the source they come from when
+// it is not empty. Their line numbers are the lines of that source
+func (m *Monitor) watch(ctx context.Context, source string, r io.Reader, emit func(Update) error) error {
+	type line struct {
+		text    string
+		tooLong bool
+		err     error
+	}
+	// The reading blocks until a line arrives, it can't be canceled, so it is
+	// done apart to stop as soon as ctx is done. Returning cancels it as well,
+	// so the reader is never left waiting to send a line nobody reads
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	lines := make(chan line)
+	go func() {
+		defer close(lines)
+		send := func(l line) bool {
+			select {
+			case lines <- l:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		reader := bufio.NewReaderSize(r, maxWatchLine)
+		for {
+			text, err := reader.ReadSlice('\n')
+			l := line{text: strings.TrimSuffix(string(text), "\n")}
+			if err == bufio.ErrBufferFull {
+				l = line{tooLong: true}
+				for err == bufio.ErrBufferFull {
+					_, err = reader.ReadSlice('\n')
+				}
+			}
+			if err != nil && err != io.EOF {
+				send(line{err: err})
+				return
+			}
+			// The input can end without a newline after the last line
+			if err == nil || l.text != "" || l.tooLong {
+				if !send(l) {
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}()
+
+	number := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case l, ok := <-lines:
+			if !ok {
+				return nil
+			}
+			if l.err != nil {
+				return l.err
+			}
+			number++
+			var update Update
+			text := strings.TrimSuffix(l.text, "\r")
+			if l.tooLong {
+				update = Update{Reading: m.readings + 1, Err: watchError(source, &aoc.ParseError{Line: number, Col: 1, Msg: fmt.Sprintf("line longer than %d bytes", maxWatchLine)})}
+			} else if depth, err := strconv.Atoi(text); err != nil {
+				update = Update{Reading: m.readings + 1, Err: watchError(source, &aoc.ParseError{Line: number, Col: 1, Msg: fmt.Sprintf("invalid reading %q", text), Err: err})}
+			} else {
+				update = m.Add(depth)
+			}
+			if err := emit(update); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Names the source of an error, when there is one
+func watchError(source string, err error) error {
+	if source == "" {
+		return err
+	}
+	return fmt.Errorf("%s: %w", source, err)
+}
+
+// WatchListener accepts the connections of sensors on l, one at a time, and
+// watches their readings. A sensor that reconnects continues the same series
+// The errors of the lines name the address of the sensor, with the line in its
+// connection. An error reading a connection only closes it. WatchListener returns when ctx
+// is done or when emit fails
+func (m *Monitor) WatchListener(ctx context.Context, l net.Listener, emit func(Update) error) error {
+	// Accept blocks as well, closing the listener stops it
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			l.Close()
+		case <-done:
+		}
+	}()
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		var emitErr error
+		// An error reading the connection only closes it
+		m.watch(ctx, "sensor "+conn.RemoteAddr().String(), conn, func(update Update) error {
+			emitErr = emit(update)
+			return emitErr
+		})
+		conn.Close()
+		if emitErr != nil {
+			return emitErr
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
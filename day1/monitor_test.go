@@ -0,0 +1,170 @@
+package day1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMonitorCounts(t *testing.T) {
+	depths, _ := Parse(Generate(2000))
+	windows := []int{1, 2, 3, 7}
+	m, err := NewMonitor(windows, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var update Update
+	for _, depth := range depths {
+		update = m.Add(depth)
+	}
+	for index, window := range windows {
+		expected := WindowCount{window, countSequential(depths, window)}
+		if update.Counts[index] != expected {
+			t.Errorf("got %+v, expected %+v", update.Counts[index], expected)
+		}
+	}
+}
+
+func TestMonitorAlerts(t *testing.T) {
+	m, _ := NewMonitor([]int{1}, 3)
+	alerts := make([]int, 0)
+	err := m.Watch(context.Background(), strings.NewReader("1\n2\n3\n4\n1\n2\nx\n3\n4\n5\n"), func(u Update) error {
+		if u.Alert != "" {
+			alerts = append(alerts, u.Reading)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The garbled line is not a reading, it does not break the streak
+	if fmt.Sprint(alerts) != "[4 8]" {
+		t.Errorf("got alerts at readings %v, expected [4 8]", alerts)
+	}
+}
+
+func TestWatchErrors(t *testing.T) {
+	m, _ := NewMonitor([]int{1, 3}, 0)
+	updates := make([]Update, 0)
+	err := m.Watch(context.Background(), strings.NewReader("199\n200\nabc\r\n208\n"), func(u Update) error {
+		updates = append(updates, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 4 || updates[2].Err == nil || !strings.Contains(updates[2].Err.Error(), "line 3") {
+		t.Fatalf("got updates %v", updates)
+	}
+	if last := updates[3]; last.Reading != 3 || last.Counts[0].Count != 2 {
+		t.Errorf("got last update %v", last)
+	}
+
+	// A line too long for a reading is skipped as well
+	updates = updates[:0]
+	err = m.Watch(context.Background(), strings.NewReader("210\n"+strings.Repeat("9", 100000)+"\n211"), func(u Update) error {
+		updates = append(updates, u)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 3 || updates[1].Err == nil || !strings.Contains(updates[1].Err.Error(), "line 2, column 1: line longer") || updates[2].Depth != 211 {
+		t.Errorf("got updates %v", updates)
+	}
+
+	stop := errors.New("stop")
+	goroutines := runtime.NumGoroutine()
+	err = m.Watch(context.Background(), strings.NewReader("1\n2\n"), func(u Update) error { return stop })
+	if err != stop {
+		t.Errorf("got error %v, expected the error of emit", err)
+	}
+	// The reading of the lines stops with Watch, even when ctx goes on
+	for deadline := time.Now().Add(5 * time.Second); runtime.NumGoroutine() > goroutines; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines left after Watch, expected %d", runtime.NumGoroutine(), goroutines)
+		}
+	}
+}
+
+// The updates come as the readings arrive, not at the end of the input
+func TestWatchLive(t *testing.T) {
+	r, w := io.Pipe()
+	updates := make(chan Update)
+	m, _ := NewMonitor([]int{1}, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- m.Watch(ctx, r, func(u Update) error {
+			updates <- u
+			return nil
+		})
+	}()
+	for index, depth := range []int{10, 20, 15} {
+		fmt.Fprintln(w, depth)
+		select {
+		case u := <-updates:
+			if u.Reading != index+1 || u.Depth != depth {
+				t.Errorf("got update %v for depth %d", u, depth)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no update for depth %d", depth)
+		}
+	}
+	// Canceling stops watching, even with no more input
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v", err)
+	}
+	w.Close()
+}
+
+func TestWatchListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, _ := NewMonitor([]int{1}, 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	updates := make(chan Update)
+	done := make(chan error)
+	go func() {
+		done <- m.WatchListener(ctx, l, func(u Update) error {
+			updates <- u
+			return nil
+		})
+	}()
+
+	// The second connection continues the series of the first one, its errors
+	// are on its own lines
+	var errs []error
+	for _, depths := range []string{"1\n2\n", "x\n3\n"} {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprint(conn, depths)
+		conn.Close()
+		for range strings.Fields(depths) {
+			if u := <-updates; u.Err != nil {
+				errs = append(errs, u.Err)
+			}
+		}
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v", err)
+	}
+	if u := m.Add(4); u.Reading != 4 || u.Counts[0].Count != 3 {
+		t.Errorf("got update %v after both connections", u)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "sensor 127.0.0.1:") || !strings.Contains(errs[0].Error(), "line 1,") {
+		t.Errorf("got errors %v, expected the invalid line of the second sensor", errs)
+	}
+}
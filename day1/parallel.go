@@ -0,0 +1,131 @@
+package day1
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// CountIncreases counts the readings deeper than the reading window positions
+// before them, in an input of size bytes with one reading per line
+// Comparing sums of windows of n readings is the same as comparing readings n
+// positions apart (see part2CompareEnds), so window 1 gives the answer to Part1
+// and window 3 the answer to Part2
+//
+// The input is never loaded as a whole: it is split in byte ranges, read and
+// counted by workers goroutines at the same time, so it works on archives of
+// hundreds of millions of readings. A range holds the lines that start in it,
+// whatever the range they end in. The counts of the ranges are then stitched
+// together with the comparisons across their boundaries
+// The input is read the same way as Parse does, with the same errors
+func CountIncreases(ctx context.Context, r io.ReaderAt, size int64, window int, workers int) (int, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	return countIncreases(ctx, r, size, window, input.Chunks(size, workers), workers)
+}
+
+// What a worker found in its byte range of the input
+type chunk struct {
+	// Number of readings in the range
+	readings int
+	// The first readings of the range, and the last ones in a ring, at most
+	// window of each. They are all the comparisons with readings out of the
+	// range need
+	head, last []int
+	// Increases between readings of the range
+	count int
+}
+
+func countIncreases(ctx context.Context, r io.ReaderAt, size int64, window int, chunks int, workers int) (int, error) {
+	if window < 1 {
+		return 0, fmt.Errorf("invalid window %d, expected 1 or more", window)
+	}
+	if size == 0 {
+		// An empty input is an error for Parse as well
+		_, err := input.Ints(nil)
+		return 0, err
+	}
+
+	ranges := make([]chunk, chunks)
+	for index := range ranges {
+		ranges[index].last = make([]int, window)
+	}
+	err := input.ChunkedLines(ctx, r, size, chunks, workers, func(index int, number int, line []byte) error {
+		c := &ranges[index]
+		value, ok := parseReading(line)
+		if !ok {
+			// Same error as Parse
+			_, atoiErr := strconv.Atoi(string(line))
+			return &aoc.ParseError{Line: number, Col: 1, Msg: "invalid integer", Err: atoiErr}
+		}
+		if c.readings >= window && value > c.last[c.readings%window] {
+			c.count++
+		}
+		c.last[c.readings%window] = value
+		if c.readings < window {
+			c.head = append(c.head, value)
+		}
+		c.readings++
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	// Stitch the ranges in order, keeping the last window readings before the range
+	total := 0
+	previous := make([]int, 0, 2*window)
+	for _, c := range ranges {
+		total += c.count
+		for index, value := range c.head {
+			// The reading to compare with is in the previous ranges
+			if before := len(previous) + index - window; before >= 0 && value > previous[before] {
+				total++
+			}
+		}
+		previous = append(previous, c.tail()...)
+		if len(previous) > window {
+			previous = append(previous[:0], previous[len(previous)-window:]...)
+		}
+	}
+	return total, nil
+}
+
+// The last readings of the range, in order, at most window of them
+func (c *chunk) tail() []int {
+	if c.readings < len(c.last) {
+		return c.head
+	}
+	// The ring starts at its oldest reading
+	oldest := c.readings % len(c.last)
+	return append(append(make([]int, 0, len(c.last)), c.last[oldest:]...), c.last[:oldest]...)
+}
+
+// Parses a reading the way strconv.Atoi does, without converting the line to a string
+func parseReading(line []byte) (int, bool) {
+	digits := line
+	if len(digits) > 0 && (digits[0] == '-' || digits[0] == '+') {
+		digits = digits[1:]
+	}
+	if len(digits) == 0 || len(digits) > 18 {
+		// Longer numbers may overflow, leave them to strconv
+		value, err := strconv.Atoi(string(line))
+		return value, err == nil
+	}
+	value := 0
+	for _, digit := range digits {
+		if digit < '0' || digit > '9' {
+			return 0, false
+		}
+		value = value*10 + int(digit-'0')
+	}
+	if line[0] == '-' {
+		value = -value
+	}
+	return value, true
+}
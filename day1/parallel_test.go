@@ -0,0 +1,91 @@
+package day1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Counts the increases over window one reading at a time, the way Part1 and Part2 do
+func countSequential(depths []int, window int) int {
+	count := 0
+	for index := window; index < len(depths); index++ {
+		if depths[index] > depths[index-window] {
+			count++
+		}
+	}
+	return count
+}
+
+func TestCountIncreases(t *testing.T) {
+	ctx := context.Background()
+	inputs := map[string][]byte{
+		"generated":        Generate(5000),
+		"trailing newline": append(Generate(100), '\n'),
+		"windows newlines": bytes.ReplaceAll(Generate(100), []byte("\n"), []byte("\r\n")),
+		"few readings":     []byte("3\n1\n2"),
+	}
+	for name, data := range inputs {
+		depths, err := Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for window := 1; window <= 5; window++ {
+			expected := countSequential(depths, window)
+			// Up to ranges of a couple of bytes, shorter than the window
+			for _, chunks := range []int{1, 2, 3, 7, 64, len(data) / 3} {
+				got, err := countIncreases(ctx, bytes.NewReader(data), int64(len(data)), window, chunks, 4)
+				if err != nil || got != expected {
+					t.Errorf("%s, window %d, %d chunks: got %d (error %v), expected %d", name, window, chunks, got, err, expected)
+				}
+			}
+		}
+	}
+
+	// The same answers as the parts
+	data := Generate(1000)
+	depths, _ := Parse(data)
+	for window, part := range map[int]func(context.Context, []int) (int, error){1: Part1, 3: Part2} {
+		expected, _ := part(ctx, depths)
+		got, err := CountIncreases(ctx, bytes.NewReader(data), int64(len(data)), window, 3)
+		if err != nil || got != expected {
+			t.Errorf("window %d: got %d (error %v), expected %d", window, got, err, expected)
+		}
+	}
+}
+
+func TestCountIncreasesErrors(t *testing.T) {
+	for _, data := range [][]byte{
+		[]byte(""),
+		[]byte("1\n2\nx\n4"),
+		[]byte("1\n2\n\n4\n"),
+		append(Generate(1000), []byte("\n12a\n5")...),
+	} {
+		_, expected := Parse(data)
+		for _, chunks := range []int{1, 5, 50} {
+			_, err := countIncreases(context.Background(), bytes.NewReader(data), int64(len(data)), 1, chunks, 2)
+			var got, want *aoc.ParseError
+			if !errors.As(err, &got) || !errors.As(expected, &want) || got.Error() != want.Error() {
+				t.Errorf("%q, %d chunks: got error %v, expected %v", data, chunks, err, expected)
+			}
+		}
+	}
+}
+
+func BenchmarkCountIncreases(b *testing.B) {
+	data := Generate(10 * benchSize)
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("%d-workers", workers), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := CountIncreases(context.Background(), bytes.NewReader(data), int64(len(data)), 3, workers); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
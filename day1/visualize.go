@@ -0,0 +1,61 @@
+package day1
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// Number of measures shown in a frame, the last ones read
+const visibleMeasures = 15
+
+// Visualize shows the measures one by one as bars, the ones deeper than the
+// previous measure highlighted, with the counts of both parts so far
+func Visualize(ctx context.Context, depths []int, show func(frame string) error) error {
+	if len(depths) == 0 {
+		return nil
+	}
+	min, max := depths[0], depths[0]
+	for _, depth := range depths {
+		if depth < min {
+			min = depth
+		}
+		if depth > max {
+			max = depth
+		}
+	}
+
+	count, windowCount := 0, 0
+	for index, depth := range depths {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if index > 0 && depth > depths[index-1] {
+			count++
+		}
+		if index >= 3 && depth > depths[index-3] {
+			windowCount++
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s - measure %d of %d\n\n", visual.Bold("Day 1"), index+1, len(depths))
+		first := index - visibleMeasures + 1
+		if first < 0 {
+			first = 0
+		}
+		for i := first; i <= index; i++ {
+			line := fmt.Sprintf("%6d %s", depths[i], strings.Repeat("#", 1+visual.Scale(depths[i], min, max, 50)))
+			if i > 0 && depths[i] > depths[i-1] {
+				line = visual.Highlight(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		fmt.Fprintf(&b, "\nIncreases: %s   Increases of the sliding window: %s", visual.Win(fmt.Sprint(count)), visual.Win(fmt.Sprint(windowCount)))
+		if err := show(b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package day2
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// ErrUnknownInstruction is returned for instructions other than up, down or
+// forward, or the ones of the model, like left and right for Model3D
+var ErrUnknownInstruction = errors.New("unknown instruction")
+
+// The instructions of the puzzle
+var words = []string{"up", "down", "forward"}
+
+type Elements struct {
+	word  string
+	value int
+}
+
+// String writes the instruction the way Parse reads it
+func (e Elements) String() string {
+	return fmt.Sprintf("%s %d", e.word, e.value)
+}
+
+type Position struct {
+	aim        int
+	depth      int
+	horizontal int
+	// Only Model3D turns: the heading in degrees, counterclockwise from the east,
+	// and how far east and north the moves forward took the submarine
+	heading     int
+	east, north float64
+}
+
+//go:embed *.txt
+var files embed.FS
+
+func init() {
+	aoc.Register(aoc.Day{
+		Number: 2,
+		Parse:  func(data []byte) (interface{}, error) { return Parse(data) },
+		Part1: []aoc.Solution{
+			{Name: "map", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part1(ctx, p.([]Elements)) }},
+			{Name: "counters", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return part1Counters(ctx, p.([]Elements))
+			}},
+			{Name: "position", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return part1Position(ctx, p.([]Elements))
+			}},
+		},
+		Part2: []aoc.Solution{
+			{Name: "position", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part2(ctx, p.([]Elements)) }},
+			{Name: "parallel-scan", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return part2Scan(ctx, p.([]Elements))
+			}},
+		},
+		Generate: Generate,
+		Visualize: func(ctx context.Context, p interface{}, show func(string) error) error {
+			return Visualize(ctx, p.([]Elements), show)
+		},
+		Files: files,
+	})
+}
+
+// Part 1: multiply depth by horizontal distance
+// The sums are answers as well, they can get larger than an int on large inputs
+func Part1(ctx context.Context, structuredInput []Elements) (aoc.Answer, error) {
+	m := make(map[string]aoc.Answer, 3)
+	for index, element := range structuredInput {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return aoc.Answer{}, err
+		}
+		m[element.word] = m[element.word].Add(aoc.Int(element.value))
+	}
+	return m["forward"].Mul(m["down"].Sub(m["up"])), nil
+}
+
+// Same as Part1 without the map: there are only 3 words, so 3 variables are enough
+// and we save hashing the word of every instruction
+func part1Counters(ctx context.Context, structuredInput []Elements) (aoc.Answer, error) {
+	forward, down, up := aoc.Int(0), aoc.Int(0), aoc.Int(0)
+	for index, element := range structuredInput {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return aoc.Answer{}, err
+		}
+		switch element.word {
+		case "forward":
+			forward = forward.Add(aoc.Int(element.value))
+		case "down":
+			down = down.Add(aoc.Int(element.value))
+		case "up":
+			up = up.Add(aoc.Int(element.value))
+		}
+	}
+	return forward.Mul(down.Sub(up)), nil
+}
+
+// Same as Part1, but moving the submarine step by step like in Part2, without the aim
+// It is the most literal reading of the puzzle, to cross-check the other solutions
+func part1Position(ctx context.Context, structuredInput []Elements) (aoc.Answer, error) {
+	depth, horizontal := aoc.Int(0), aoc.Int(0)
+	for index, element := range structuredInput {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return aoc.Answer{}, err
+		}
+		switch element.word {
+		case "down":
+			depth = depth.Add(aoc.Int(element.value))
+		case "up":
+			depth = depth.Sub(aoc.Int(element.value))
+		case "forward":
+			horizontal = horizontal.Add(aoc.Int(element.value))
+		default:
+			return aoc.Answer{}, fmt.Errorf("instruction %d: %w: %q", index+1, ErrUnknownInstruction, element.word)
+		}
+	}
+	return depth.Mul(horizontal), nil
+}
+
+// Part 2: Different instructions, run new depth * horizontal distance
+// The depth grows by products of the aim and the moves forward, so it can get
+// larger than an int on large inputs, it is kept as an answer with the aim and
+// the horizontal position
+func Part2(ctx context.Context, structuredInput []Elements) (aoc.Answer, error) {
+	aim, horizontal, depth := aoc.Int(0), aoc.Int(0), aoc.Int(0)
+	for index, element := range structuredInput {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return aoc.Answer{}, err
+		}
+		value := aoc.Int(element.value)
+		switch element.word {
+		case "down":
+			aim = aim.Add(value)
+		case "up":
+			aim = aim.Sub(value)
+		case "forward":
+			horizontal = horizontal.Add(value)
+			depth = depth.Add(aim.Mul(value))
+		default:
+			return aoc.Answer{}, fmt.Errorf("instruction %d: %w: %q", index+1, ErrUnknownInstruction, element.word)
+		}
+	}
+
+	return depth.Mul(horizontal), nil
+}
+
+// Parse reads the input file. It contains a list of instruction composed of
+// a string and an integer
+// up 3, down 5, forward 7, etc
+func Parse(file []byte) ([]Elements, error) {
+	return parse(file, words)
+}
+
+// Same as Parse, with the instructions in words only
+func parse(file []byte, words []string) ([]Elements, error) {
+	lines := input.Lines(file)
+
+	// When knowing the size, it's better to allocate the right size immediately
+	// as append() has a cost
+	// https://medium.com/vendasta/golang-the-time-complexity-of-append-2177dcfb6bad
+	// /!\ Do not use make([]Elements, len(lines)) as it will give it cap AND size len(lines), and
+	// appending to it will just append after the last element, so the first elements will be 0
+	structuredInput := make([]Elements, 0, len(lines))
+
+	for index, line := range lines {
+		element, err := parseLine(line, index+1, words)
+		if err != nil {
+			return nil, err
+		}
+		structuredInput = append(structuredInput, element)
+	}
+
+	return structuredInput, nil
+}
+
+// Reads the instruction of a line, lineNumber is the number of the line for the errors
+func parseLine(line string, lineNumber int, words []string) (Elements, error) {
+	// A line is supposed to be composed of a single word, a white space and an integer
+	parts := strings.Split(line, " ")
+	if len(parts) != 2 {
+		return Elements{}, &aoc.ParseError{Line: lineNumber, Col: 1, Msg: fmt.Sprintf("expected \"string int\", found %q", line)}
+	}
+	if !isWord(parts[0], words) {
+		return Elements{}, &aoc.ParseError{Line: lineNumber, Col: 1, Msg: fmt.Sprintf("%q", parts[0]), Err: fmt.Errorf("%w, expected one of %v", ErrUnknownInstruction, words)}
+	}
+	// Get the integer value from the line
+	value, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return Elements{}, &aoc.ParseError{Line: lineNumber, Col: len(parts[0]) + 2, Msg: "invalid value", Err: err}
+	}
+	return Elements{parts[0], value}, nil
+}
+
+func isWord(word string, words []string) bool {
+	for _, w := range words {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package day2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/aoc/aoctest"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// The examples given in the puzzle, with their answers
+var examples = []aoctest.Example{
+	{File: "example.txt", Part1: 150, Part2: 900},
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.CheckExamples(t, 2, examples)
+}
+
+func TestSolutionsAgree(t *testing.T) {
+	aoctest.CheckSolutions(t, 2, Generate(1000))
+}
+
+func TestCanceled(t *testing.T) {
+	aoctest.CheckCanceled(t, 2, Generate(1000))
+}
+
+func TestVisualize(t *testing.T) {
+	aoctest.CheckVisualize(t, 2, Generate(100))
+}
+
+// Answers that don't fit in 64 bits, and sums of instructions that don't either
+func TestLargeAnswers(t *testing.T) {
+	day, _ := aoc.Lookup(2)
+	for _, test := range []struct {
+		data     string
+		expected [2]string
+	}{
+		{"down 2\nforward 9223372036854775807\n", [2]string{"18446744073709551614", "170141183460469231694793815568465002498"}},
+		{"down 9223372036854775807\ndown 1\nforward 1\nforward 9223372036854775807\n", [2]string{"85070591730234615865843651857942052864", "784637716923335095479473677900958302012794430558004314112"}},
+	} {
+		data := []byte(test.data)
+		result, err := aoc.Run(context.Background(), day, data, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for index, expected := range test.expected {
+			part := result.Parts[index]
+			if part.Err != nil || part.Answer.String() != expected {
+				t.Errorf("%q part %d: got %s (error %v), expected %s", test.data, index+1, part.Answer, part.Err, expected)
+			}
+		}
+		aoctest.CheckSolutions(t, 2, data)
+	}
+}
+
+// Number of instructions in the synthetic input used by the benchmarks
+// It is well above the size of the real input
+const benchSize = 100000
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, 2, Generate(benchSize))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, 2, 1, Generate(benchSize))
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, 2, 2, Generate(benchSize))
+}
+
+func FuzzParse(f *testing.F) {
+	for _, example := range examples {
+		data, err := files.ReadFile(example.File)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(Generate(10))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		structuredInput, err := Parse(data)
+		if err == nil && len(structuredInput) != len(input.Lines(data)) {
+			t.Errorf("got %d instructions for %d lines", len(structuredInput), len(input.Lines(data)))
+		}
+	})
+}
@@ -0,0 +1,23 @@
+package day2
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Generate returns a synthetic input of n instructions
+// There are more `down` than `up`, so the submarine keeps diving like in the real inputs
+func Generate(n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	words := []string{"forward", "forward", "down", "down", "up"}
+	data := make([]byte, 0, n*10)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			data = append(data, '\n')
+		}
+		data = append(data, words[r.Intn(len(words))]...)
+		data = append(data, ' ')
+		data = strconv.AppendInt(data, int64(1+r.Intn(9)), 10)
+	}
+	return data
+}
@@ -0,0 +1,158 @@
+package day2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Bounds are the values a quantity is allowed, Min and Max included when they
+// are set. The zero Bounds allow any value
+type Bounds struct {
+	Min, Max       int
+	HasMin, HasMax bool
+}
+
+// Unbounded allows any value
+var Unbounded = Bounds{}
+
+// Between returns the bounds from min to max
+func Between(min int, max int) Bounds {
+	return Bounds{Min: min, Max: max, HasMin: true, HasMax: true}
+}
+
+// ParseBounds reads bounds like `0:2000`, the open sides are unbounded: `0:`
+// is 0 or more, `:` anything
+func ParseBounds(s string) (Bounds, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return Bounds{}, fmt.Errorf("invalid bounds %q, expected min:max", s)
+	}
+	var bounds Bounds
+	for index, limit := range []struct {
+		value *int
+		set   *bool
+	}{{&bounds.Min, &bounds.HasMin}, {&bounds.Max, &bounds.HasMax}} {
+		if parts[index] == "" {
+			continue
+		}
+		value, err := strconv.Atoi(parts[index])
+		if err != nil {
+			return Bounds{}, fmt.Errorf("invalid bounds %q: %w", s, err)
+		}
+		*limit.value, *limit.set = value, true
+	}
+	if bounds.HasMin && bounds.HasMax && bounds.Min > bounds.Max {
+		return Bounds{}, fmt.Errorf("invalid bounds %q, the min is larger than the max", s)
+	}
+	return bounds, nil
+}
+
+func (b Bounds) String() string {
+	s := ""
+	if b.HasMin {
+		s += strconv.Itoa(b.Min)
+	}
+	s += ":"
+	if b.HasMax {
+		s += strconv.Itoa(b.Max)
+	}
+	return s
+}
+
+// Returns the value in the bounds closest to value
+func (b Bounds) clamp(value int) int {
+	if b.HasMin && value < b.Min {
+		return b.Min
+	}
+	if b.HasMax && value > b.Max {
+		return b.Max
+	}
+	return value
+}
+
+// Mode is what happens when an instruction breaks the limits, the empty mode
+// is Halt
+type Mode string
+
+const (
+	// Halt stops the submarine before the instruction
+	Halt Mode = "halt"
+	// Clamp runs the instruction, and brings the quantities back in bounds
+	Clamp Mode = "clamp"
+)
+
+// Limits are the invariants checked after every instruction
+// They bound the quantities of the puzzle, east and north of Model3D are not
+// bounded nor clamped. The quantities left out are unbounded
+type Limits struct {
+	Depth, Aim, Horizontal Bounds
+	Mode                   Mode
+}
+
+// NoLimits lets the submarine go anywhere, like the puzzle does
+var NoLimits = Limits{}
+
+// Violation is an instruction that breaks the limits
+type Violation struct {
+	// Line is the number of the instruction, from 1, and its line in the input
+	Line        int
+	Instruction Elements
+	// Quantity is what gets out of bounds: depth, aim or horizontal
+	Quantity string
+	// Value is the value of the quantity after the instruction, before clamping
+	Value  int
+	Bounds Bounds
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("line %d: %q takes the %s to %d, out of bounds %s", v.Line, v.Instruction, v.Quantity, v.Value, v.Bounds)
+}
+
+// SimulateWithin moves the submarine like Simulate, checking the limits after
+// every instruction. With Halt, the first violation is returned as an error,
+// with the position before it. With Clamp, every violation is returned, in
+// order, with the position at the end
+func (m Model) SimulateWithin(ctx context.Context, instructions []Elements, limits Limits) (Position, []Violation, error) {
+	if limits.Mode == "" {
+		limits.Mode = Halt
+	}
+	if limits.Mode != Halt && limits.Mode != Clamp {
+		return Position{}, nil, fmt.Errorf("invalid mode %q, expected %s or %s", limits.Mode, Halt, Clamp)
+	}
+	violations := make([]Violation, 0)
+	position := Position{}
+	for index, element := range instructions {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return Position{}, nil, err
+		}
+		next, err := m.Move(position, element)
+		if err != nil {
+			return Position{}, nil, fmt.Errorf("instruction %d: %w", index+1, err)
+		}
+		for _, check := range []struct {
+			quantity string
+			value    *int
+			bounds   Bounds
+		}{
+			{"depth", &next.depth, limits.Depth},
+			{"aim", &next.aim, limits.Aim},
+			{"horizontal", &next.horizontal, limits.Horizontal},
+		} {
+			clamped := check.bounds.clamp(*check.value)
+			if clamped == *check.value {
+				continue
+			}
+			violations = append(violations, Violation{index + 1, element, check.quantity, *check.value, check.bounds})
+			if limits.Mode == Halt {
+				return position, violations, &violations[0]
+			}
+			*check.value = clamped
+		}
+		position = next
+	}
+	return position, violations, nil
+}
@@ -0,0 +1,72 @@
+package day2
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseBounds(t *testing.T) {
+	for _, test := range []struct {
+		s        string
+		expected Bounds
+	}{
+		{"0:2000", Between(0, 2000)},
+		{"-5:", Bounds{Min: -5, HasMin: true}},
+		{":10", Bounds{Max: 10, HasMax: true}},
+		{":", Unbounded},
+	} {
+		bounds, err := ParseBounds(test.s)
+		if err != nil || bounds != test.expected || bounds.String() != test.s {
+			t.Errorf("%q: got %v (%s), %v, expected %v", test.s, bounds, bounds, err, test.expected)
+		}
+	}
+	for _, s := range []string{"", "5", "1:2:3", "a:", "10:5"} {
+		if _, err := ParseBounds(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestSimulateWithin(t *testing.T) {
+	instructions, _ := Parse([]byte("down 5\nforward 2\nup 8\nforward 4\nforward 10\n"))
+
+	// The puzzle goes anywhere
+	position, violations, err := AimModel.SimulateWithin(context.Background(), instructions, NoLimits)
+	expected, _ := AimModel.Simulate(context.Background(), instructions)
+	if err != nil || len(violations) != 0 || position != expected {
+		t.Errorf("no limits: got %s, %v, %v, expected %s", position, violations, err, expected)
+	}
+
+	// Only the depth and the horizontal position are bounded, the aim goes
+	// below 0 without violations
+	limits := Limits{Depth: Bounds{Min: 0, HasMin: true}, Horizontal: Bounds{Max: 12, HasMax: true}}
+
+	// Halts before going above the surface, at line 4
+	position, violations, err = AimModel.SimulateWithin(context.Background(), instructions, limits)
+	var violation *Violation
+	if !errors.As(err, &violation) || violation.Line != 4 || violation.Quantity != "depth" || violation.Value != -2 {
+		t.Errorf("halt: got %v, expected line 4 to take the depth to -2", err)
+	}
+	if position != NewPosition(2, 10, -3) || len(violations) != 1 {
+		t.Errorf("halt: got %s, %v, expected to stop before line 4", position, violations)
+	}
+
+	// Clamps the depth at line 4, then the depth and the horizontal position at line 5
+	limits.Mode = Clamp
+	position, violations, err = AimModel.SimulateWithin(context.Background(), instructions, limits)
+	expectedViolations := []Violation{
+		{4, Elements{"forward", 4}, "depth", -2, limits.Depth},
+		{5, Elements{"forward", 10}, "depth", -30, limits.Depth},
+		{5, Elements{"forward", 10}, "horizontal", 16, limits.Horizontal},
+	}
+	if err != nil || position != NewPosition(12, 0, -3) || !reflect.DeepEqual(violations, expectedViolations) {
+		t.Errorf("clamp: got %s, %v, %v, expected %v", position, violations, err, expectedViolations)
+	}
+
+	limits.Mode = "ignore"
+	if _, _, err := AimModel.SimulateWithin(context.Background(), instructions, limits); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+}
@@ -0,0 +1,141 @@
+package day2
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Model is the physics of the submarine, the way an instruction moves it
+type Model string
+
+const (
+	// PlainModel is the physics of Part1: down and up change the depth
+	PlainModel Model = "plain"
+	// AimModel is the physics of Part2: down and up change the aim, and forward
+	// dives by the aim times the move
+	AimModel Model = "aim"
+	// Model3D is the aim model in 3 dimensions: left and right turn the
+	// submarine by a number of degrees, and forward moves it in its heading.
+	// The horizontal position is the distance travelled, and the submarine
+	// starts heading east, so without turns east is the horizontal position
+	Model3D Model = "3d"
+)
+
+// Models are all the models, in the order they are listed to the user
+var Models = []Model{PlainModel, AimModel, Model3D}
+
+// Words returns the instructions the model knows
+func (m Model) Words() []string {
+	if m == Model3D {
+		return append(append([]string{}, words...), "left", "right")
+	}
+	return words
+}
+
+// Parse reads instructions like Parse does, with the words of the model
+func (m Model) Parse(data []byte) ([]Elements, error) {
+	return parse(data, m.Words())
+}
+
+// NewPosition returns a position, for the tools that give a target
+func NewPosition(horizontal int, depth int, aim int) Position {
+	return Position{aim: aim, depth: depth, horizontal: horizontal}
+}
+
+func (p Position) Horizontal() int { return p.horizontal }
+func (p Position) Depth() int      { return p.depth }
+func (p Position) Aim() int        { return p.aim }
+func (p Position) Heading() int    { return p.heading }
+func (p Position) East() float64   { return p.east }
+func (p Position) North() float64  { return p.north }
+
+func (p Position) String() string {
+	s := fmt.Sprintf("horizontal %d, depth %d, aim %d", p.horizontal, p.depth, p.aim)
+	if p.heading != 0 || p.east != 0 || p.north != 0 {
+		s += fmt.Sprintf(", heading %d°, east %.3f, north %.3f", p.heading, p.east, p.north)
+	}
+	return s
+}
+
+// Move returns the position after an instruction
+func (m Model) Move(p Position, element Elements) (Position, error) {
+	switch {
+	case m == PlainModel && element.word == "down":
+		p.depth += element.value
+	case m == PlainModel && element.word == "up":
+		p.depth -= element.value
+	case m == PlainModel && element.word == "forward":
+		p.horizontal += element.value
+	case m == AimModel && element.word == "down":
+		p.aim += element.value
+	case m == AimModel && element.word == "up":
+		p.aim -= element.value
+	case m == AimModel && element.word == "forward":
+		p.horizontal += element.value
+		p.depth += p.aim * element.value
+	case m == Model3D && element.word == "down":
+		p.aim += element.value
+	case m == Model3D && element.word == "up":
+		p.aim -= element.value
+	case m == Model3D && element.word == "left":
+		p.heading = turn(p.heading, element.value)
+	case m == Model3D && element.word == "right":
+		p.heading = turn(p.heading, -element.value)
+	case m == Model3D && element.word == "forward":
+		p.horizontal += element.value
+		p.depth += p.aim * element.value
+		cos, sin := direction(p.heading)
+		p.east += float64(element.value) * cos
+		p.north += float64(element.value) * sin
+	case m != PlainModel && m != AimModel && m != Model3D:
+		return p, fmt.Errorf("invalid model %q, expected one of %v", m, Models)
+	default:
+		return p, fmt.Errorf("%w: %q, expected one of %v", ErrUnknownInstruction, element.word, m.Words())
+	}
+	return p, nil
+}
+
+// Returns the heading after turning by some degrees, from 0 to 359
+func turn(heading int, degrees int) int {
+	heading = (heading + degrees) % 360
+	if heading < 0 {
+		heading += 360
+	}
+	return heading
+}
+
+// Returns the cosine and the sine of a heading, exact for the right angles so
+// a submarine that only turns by right angles stays on whole positions
+func direction(heading int) (float64, float64) {
+	switch heading {
+	case 0:
+		return 1, 0
+	case 90:
+		return 0, 1
+	case 180:
+		return -1, 0
+	case 270:
+		return 0, -1
+	}
+	radians := float64(heading) * math.Pi / 180
+	return math.Cos(radians), math.Sin(radians)
+}
+
+// Simulate moves the submarine from the surface through the instructions and
+// returns where it ends
+func (m Model) Simulate(ctx context.Context, instructions []Elements) (Position, error) {
+	position := Position{}
+	for index, element := range instructions {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return Position{}, err
+		}
+		var err error
+		if position, err = m.Move(position, element); err != nil {
+			return Position{}, fmt.Errorf("instruction %d: %w", index+1, err)
+		}
+	}
+	return position, nil
+}
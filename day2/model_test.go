@@ -0,0 +1,70 @@
+package day2
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// The models give the answers of the parts they are the physics of
+func TestModels(t *testing.T) {
+	instructions, _ := Parse(Generate(1000))
+	for _, test := range []struct {
+		model Model
+		part  func(context.Context, []Elements) (aoc.Answer, error)
+	}{{PlainModel, Part1}, {AimModel, Part2}} {
+		position, err := test.model.Simulate(context.Background(), instructions)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := test.part(context.Background(), instructions)
+		if answer := aoc.Product(position.Horizontal(), position.Depth()); !answer.Equal(expected) {
+			t.Errorf("%s: got %s, expected %s", test.model, answer, expected)
+		}
+	}
+
+	if _, err := AimModel.Simulate(context.Background(), []Elements{{"forward", 1}, {"back", 1}}); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("got %v, expected %v", err, ErrUnknownInstruction)
+	}
+}
+
+func TestModel3D(t *testing.T) {
+	// Without turns, it is the aim model heading east
+	instructions, _ := Parse(Generate(1000))
+	aim, _ := AimModel.Simulate(context.Background(), instructions)
+	position, err := Model3D.Simulate(context.Background(), instructions)
+	if err != nil || position.Horizontal() != aim.Horizontal() || position.Depth() != aim.Depth() || position.East() != float64(aim.Horizontal()) || position.North() != 0 {
+		t.Errorf("got %s, %v, expected %s heading east", position, err, aim)
+	}
+
+	for _, test := range []struct {
+		input                string
+		heading, east, north float64
+	}{
+		// A square, back where it started
+		{"forward 5\nleft 90\nforward 5\nleft 90\nforward 5\nleft 90\nforward 5\nleft 90", 0, 0, 0},
+		{"right 90\nforward 3\nright 450\nforward 4", 180, -4, -3},
+		{"left 45\nforward 10", 45, 7.0710678, 7.0710678},
+		{"right 30\nforward 2", 330, 1.7320508, -1},
+	} {
+		instructions, err := Model3D.Parse([]byte(test.input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		position, err := Model3D.Simulate(context.Background(), instructions)
+		if err != nil || float64(position.Heading()) != test.heading || math.Abs(position.East()-test.east) > 1e-6 || math.Abs(position.North()-test.north) > 1e-6 {
+			t.Errorf("%q: got %s, %v, expected heading %g, east %g, north %g", test.input, position, err, test.heading, test.east, test.north)
+		}
+	}
+
+	// The turns are not instructions of the puzzle
+	if _, err := Parse([]byte("left 90")); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("got %v, expected %v", err, ErrUnknownInstruction)
+	}
+	if _, err := AimModel.Simulate(context.Background(), []Elements{{"left", 90}}); !errors.Is(err, ErrUnknownInstruction) {
+		t.Errorf("got %v, expected %v", err, ErrUnknownInstruction)
+	}
+}
@@ -0,0 +1,150 @@
+package day2
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnreachable is returned by Plan for targets no instructions can reach
+var ErrUnreachable = errors.New("target unreachable")
+
+// PlanOptions are the model and the constraints of a plan
+type PlanOptions struct {
+	Model Model
+	// MaxStep is the largest value of an instruction, 0 for no limit
+	MaxStep int
+	// AboveSurface allows plans where the depth gets negative on the way
+	AboveSurface bool
+	// MatchAim makes the plan end with the aim of the target as well, for the
+	// aim model. Otherwise the aim at the end is whatever is shortest
+	MatchAim bool
+	// MaxLength is the largest number of instructions of a plan, 0 for no limit
+	MaxLength int
+}
+
+// Plan returns instructions that move the submarine from the surface to the
+// target, the inverse of Simulate. Every instruction has a value from 1 to
+// MaxStep, so the plan is valid input for Parse
+//
+// With the plain model, the plan is the shortest: the dives, then the moves
+// forward. With the aim model, the depth is aim times the moves forward, at
+// every aim on the way. The plan uses two aims next to each other, q and q+1
+// with q the depth divided by the horizontal position: q+1 for the remainder
+// of the division, q for the rest of the way, in the order that makes the
+// shorter plan. The depth only moves toward the target, never past it, so the
+// submarine stays under the surface on the way to a target under it
+// With Model3D, the plan is the plan of the aim model, heading east all the way
+func Plan(target Position, options PlanOptions) ([]Elements, error) {
+	if options.MaxStep < 0 {
+		return nil, fmt.Errorf("invalid max step %d, expected 0 or more", options.MaxStep)
+	}
+	if target.horizontal < 0 {
+		return nil, fmt.Errorf("%w: %s, the submarine only moves forward", ErrUnreachable, target)
+	}
+	if target.depth < 0 && !options.AboveSurface {
+		return nil, fmt.Errorf("%w: %s, above the surface", ErrUnreachable, target)
+	}
+
+	p := planner{maxStep: options.MaxStep, maxLength: options.MaxLength, plan: make([]Elements, 0)}
+	switch options.Model {
+	case PlainModel:
+		if options.MatchAim {
+			return nil, fmt.Errorf("the %s model has no aim to match", PlainModel)
+		}
+		p.steps("down", target.depth)
+		p.steps("up", -target.depth)
+		p.steps("forward", target.horizontal)
+	case AimModel, Model3D:
+		if target.horizontal == 0 {
+			// Only the moves forward change the depth
+			if target.depth != 0 {
+				return nil, fmt.Errorf("%w: %s, the depth changes only moving forward", ErrUnreachable, target)
+			}
+			if options.MatchAim {
+				p.aimTo(target.aim)
+			}
+		} else {
+			// Floor division, the remainder is from 0 to horizontal-1
+			q, r := target.depth/target.horizontal, target.depth%target.horizontal
+			if r < 0 {
+				q, r = q-1, r+target.horizontal
+			}
+			first, second := planner{maxStep: p.maxStep, maxLength: p.maxLength}, planner{maxStep: p.maxStep, maxLength: p.maxLength}
+			for _, order := range []struct {
+				p      *planner
+				levels [2][2]int
+			}{
+				{&first, [2][2]int{{q + 1, r}, {q, target.horizontal - r}}},
+				{&second, [2][2]int{{q, target.horizontal - r}, {q + 1, r}}},
+			} {
+				for _, level := range order.levels {
+					if level[1] > 0 {
+						order.p.aimTo(level[0])
+						order.p.steps("forward", level[1])
+					}
+				}
+				if options.MatchAim {
+					order.p.aimTo(target.aim)
+				}
+			}
+			p = first
+			if second.length < first.length {
+				p = second
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid model %q, expected one of %v", options.Model, Models)
+	}
+
+	if options.MaxLength > 0 && p.length > options.MaxLength {
+		return nil, fmt.Errorf("%w: %s, the shortest plan found has %d instructions, more than %d", ErrUnreachable, target, p.length, options.MaxLength)
+	}
+	return p.plan, nil
+}
+
+// Appends the instructions of a plan
+type planner struct {
+	maxStep int
+	// With a max length, the instructions past it are counted but not appended,
+	// the plan is too long anyway
+	maxLength int
+	plan      []Elements
+	// Number of instructions of the plan, appended or not
+	length int
+	// Aim after the instructions so far
+	aim int
+}
+
+// Moves by total with as few instructions as the max step allows, nothing when
+// total is 0 or less
+func (p *planner) steps(word string, total int) {
+	if total <= 0 {
+		return
+	}
+	count := 1
+	if p.maxStep > 0 {
+		count = total / p.maxStep
+		if total%p.maxStep != 0 {
+			count++
+		}
+	}
+	p.length += count
+	if p.maxLength > 0 && p.length > p.maxLength {
+		return
+	}
+	for total > 0 {
+		step := total
+		if p.maxStep > 0 && step > p.maxStep {
+			step = p.maxStep
+		}
+		p.plan = append(p.plan, Elements{word, step})
+		total -= step
+	}
+}
+
+// Turns the aim model to an aim
+func (p *planner) aimTo(aim int) {
+	p.steps("down", aim-p.aim)
+	p.steps("up", p.aim-aim)
+	p.aim = aim
+}
@@ -0,0 +1,115 @@
+package day2
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestPlan(t *testing.T) {
+	for _, model := range Models {
+		for _, target := range []Position{
+			NewPosition(0, 0, 0),
+			NewPosition(15, 10, 0),
+			NewPosition(15, 60, 10),
+			NewPosition(7, 23, -4),
+			NewPosition(1000, 999999, 3),
+			NewPosition(5, -12, 2),
+		} {
+			for _, maxStep := range []int{0, 1, 4} {
+				options := PlanOptions{Model: model, MaxStep: maxStep, AboveSurface: target.depth < 0, MatchAim: model != PlainModel}
+				plan, err := Plan(target, options)
+				if err != nil {
+					t.Errorf("%s, %+v: %v", target, options, err)
+					continue
+				}
+				expected := target
+				switch model {
+				case PlainModel:
+					expected.aim = 0
+				case Model3D:
+					// Heading east all the way
+					expected.east = float64(target.horizontal)
+				}
+				position := Position{}
+				for index, element := range plan {
+					if element.value < 1 || maxStep > 0 && element.value > maxStep {
+						t.Errorf("%s, %+v: instruction %d is %s", target, options, index+1, element)
+					}
+					position, _ = model.Move(position, element)
+					if target.depth >= 0 && position.depth < 0 {
+						t.Errorf("%s, %+v: instruction %d goes above the surface", target, options, index+1)
+					}
+				}
+				if position != expected {
+					t.Errorf("%s, %+v: got to %s", target, options, position)
+				}
+			}
+		}
+	}
+}
+
+func TestPlanLength(t *testing.T) {
+	for _, test := range []struct {
+		target   Position
+		options  PlanOptions
+		expected int
+	}{
+		{NewPosition(15, 10, 0), PlanOptions{Model: PlainModel}, 2},
+		{NewPosition(15, 10, 0), PlanOptions{Model: PlainModel, MaxStep: 5}, 5},
+		// Aim 2 all the way
+		{NewPosition(15, 30, 0), PlanOptions{Model: AimModel}, 2},
+		// Aim 2 for 5, then aim 1 for 10: down 2, forward 5, up 1, forward 10
+		{NewPosition(15, 20, 0), PlanOptions{Model: AimModel}, 4},
+		// Aim 1 for 10, then aim 2 for 5, ending on the aim of the target
+		{NewPosition(15, 20, 2), PlanOptions{Model: AimModel, MatchAim: true}, 4},
+		// Exactly as long as the max length
+		{NewPosition(15, 10, 0), PlanOptions{Model: PlainModel, MaxStep: 5, MaxLength: 5}, 5},
+	} {
+		plan, err := Plan(test.target, test.options)
+		if err != nil || len(plan) != test.expected {
+			t.Errorf("%s, %+v: got %v, %v, expected %d instructions", test.target, test.options, plan, err, test.expected)
+		}
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	for _, test := range []struct {
+		target      Position
+		options     PlanOptions
+		unreachable bool
+	}{
+		{NewPosition(-1, 0, 0), PlanOptions{Model: AimModel}, true},
+		{NewPosition(10, -5, 0), PlanOptions{Model: AimModel}, true},
+		{NewPosition(0, 5, 0), PlanOptions{Model: AimModel}, true},
+		{NewPosition(100, 5, 0), PlanOptions{Model: PlainModel, MaxStep: 10, MaxLength: 10}, true},
+		// Too far for the plan to be built at all
+		{NewPosition(1<<50, 1<<50, 0), PlanOptions{Model: PlainModel, MaxStep: 1, MaxLength: 1000}, true},
+		{NewPosition(1<<50, 1<<52, 7), PlanOptions{Model: AimModel, MaxStep: 1, MaxLength: 1000, MatchAim: true}, true},
+		{NewPosition(10, 5, 1), PlanOptions{Model: PlainModel, MatchAim: true}, false},
+		{NewPosition(10, 5, 0), PlanOptions{Model: "rocket"}, false},
+		{NewPosition(10, 5, 0), PlanOptions{Model: AimModel, MaxStep: -1}, false},
+	} {
+		_, err := Plan(test.target, test.options)
+		if err == nil || errors.Is(err, ErrUnreachable) != test.unreachable {
+			t.Errorf("%s, %+v: got %v, expected an error, unreachable: %t", test.target, test.options, err, test.unreachable)
+		}
+	}
+}
+
+// A plan is valid input, and gives back the target
+func TestPlanParse(t *testing.T) {
+	plan, _ := Plan(NewPosition(2000, 1000000, 0), PlanOptions{Model: AimModel, MaxStep: 9})
+	data := make([]byte, 0)
+	for _, element := range plan {
+		data = append(data, element.String()+"\n"...)
+	}
+	instructions, err := Parse(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	position, err := AimModel.Simulate(context.Background(), instructions)
+	if err != nil || position.Horizontal() != 2000 || position.Depth() != 1000000 {
+		t.Errorf("got %s, %v, expected horizontal 2000, depth 1000000", position, err)
+	}
+}
@@ -0,0 +1,149 @@
+package day2
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// Runs of instructions are at least that long, shorter inputs are split in
+// fewer runs than workers
+const minScanSize = 1 << 14
+
+// summary is what a run of instructions does in the aim model, wherever it
+// starts: the aim and the horizontal position it adds, and the depth it adds
+// when it starts with an aim of 0
+// Starting with an aim a instead, the depth it adds is larger by a times the
+// horizontal position it adds. So the summary of two runs one after the other
+// only needs the summaries of the runs: summaries can be made in parallel, then
+// combined in order, the way a parallel prefix sum is
+type summary struct {
+	aim, horizontal, depth aoc.Answer
+}
+
+// Returns the summary of the run of a, then the run of b
+func (a summary) then(b summary) summary {
+	return summary{
+		aim:        a.aim.Add(b.aim),
+		horizontal: a.horizontal.Add(b.horizontal),
+		depth:      a.depth.Add(b.depth).Add(a.aim.Mul(b.horizontal)),
+	}
+}
+
+// Adds an instruction at the end of the run
+func (s *summary) add(element Elements) error {
+	value := aoc.Int(element.value)
+	switch element.word {
+	case "down":
+		s.aim = s.aim.Add(value)
+	case "up":
+		s.aim = s.aim.Sub(value)
+	case "forward":
+		s.horizontal = s.horizontal.Add(value)
+		s.depth = s.depth.Add(s.aim.Mul(value))
+	default:
+		return ErrUnknownInstruction
+	}
+	return nil
+}
+
+// The answer of Part2, for a run from the surface
+func (s summary) answer() aoc.Answer {
+	return s.depth.Mul(s.horizontal)
+}
+
+// Same as Part2, with the instructions split in runs summarized in parallel
+func part2Scan(ctx context.Context, structuredInput []Elements) (aoc.Answer, error) {
+	runs := runtime.GOMAXPROCS(0)
+	if max := len(structuredInput)/minScanSize + 1; runs > max {
+		runs = max
+	}
+	return scanElements(ctx, structuredInput, runs)
+}
+
+func scanElements(ctx context.Context, structuredInput []Elements, runs int) (aoc.Answer, error) {
+	summaries := make([]summary, runs)
+	errs := make([]error, runs)
+	var wg sync.WaitGroup
+	for run := 0; run < runs; run++ {
+		wg.Add(1)
+		go func(run int) {
+			defer wg.Done()
+			start, end := len(structuredInput)*run/runs, len(structuredInput)*(run+1)/runs
+			s := summary{depth: aoc.Int(0)}
+			for index := start; index < end; index++ {
+				if err := aoc.Canceled(ctx, index-start); err != nil {
+					errs[run] = err
+					return
+				}
+				if err := s.add(structuredInput[index]); err != nil {
+					errs[run] = fmt.Errorf("instruction %d: %w: %q", index+1, err, structuredInput[index].word)
+					return
+				}
+			}
+			summaries[run] = s
+		}(run)
+	}
+	wg.Wait()
+
+	total := summary{depth: aoc.Int(0)}
+	for run, s := range summaries {
+		if errs[run] != nil {
+			return aoc.Answer{}, errs[run]
+		}
+		total = total.then(s)
+	}
+	return total.answer(), nil
+}
+
+// ScanPart2 returns the answer to Part2 for an input of size bytes, with one
+// instruction per line
+// As day1.CountIncreases does, the input is never loaded as a whole: it is
+// split in byte ranges, and the instructions of each range are read and
+// summarized by workers goroutines at the same time, so it works on logs of
+// billions of instructions. A range holds the lines that start in it
+// The input is read the same way as Parse does, with the same errors
+func ScanPart2(ctx context.Context, r io.ReaderAt, size int64, workers int) (aoc.Answer, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	return scanPart2(ctx, r, size, input.Chunks(size, workers), workers)
+}
+
+func scanPart2(ctx context.Context, r io.ReaderAt, size int64, chunks int, workers int) (aoc.Answer, error) {
+	if size == 0 {
+		// An empty input is an error for Parse as well
+		_, err := Parse(nil)
+		return aoc.Answer{}, err
+	}
+
+	// The summary of the instructions of each byte range
+	summaries := make([]summary, chunks)
+	for index := range summaries {
+		summaries[index].depth = aoc.Int(0)
+	}
+	err := input.ChunkedLines(ctx, r, size, chunks, workers, func(index int, number int, line []byte) error {
+		element, err := parseLine(string(line), number, words)
+		if err != nil {
+			return err
+		}
+		if err := summaries[index].add(element); err != nil {
+			return &aoc.ParseError{Line: number, Col: 1, Msg: "invalid instruction", Err: err}
+		}
+		return nil
+	})
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+
+	total := summary{depth: aoc.Int(0)}
+	for _, s := range summaries {
+		total = total.then(s)
+	}
+	return total.answer(), nil
+}
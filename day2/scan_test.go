@@ -0,0 +1,87 @@
+package day2
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+func TestScanElements(t *testing.T) {
+	ctx := context.Background()
+	for _, data := range [][]byte{Generate(1000), []byte("down 2\nforward 9223372036854775807\nup 5\nforward 3")} {
+		structuredInput, _ := Parse(data)
+		expected, _ := Part2(ctx, structuredInput)
+		// Up to runs of a single instruction, and runs without any
+		for _, runs := range []int{1, 2, 3, 7, len(structuredInput), len(structuredInput) + 3} {
+			got, err := scanElements(ctx, structuredInput, runs)
+			if err != nil || !got.Equal(expected) {
+				t.Errorf("%d runs: got %s (error %v), expected %s", runs, got, err, expected)
+			}
+		}
+	}
+}
+
+func TestScanPart2(t *testing.T) {
+	ctx := context.Background()
+	inputs := map[string][]byte{
+		"generated":        Generate(5000),
+		"trailing newline": append(Generate(100), '\n'),
+		"windows newlines": bytes.ReplaceAll(Generate(100), []byte("\n"), []byte("\r\n")),
+		"few instructions": []byte("down 3\nforward 2"),
+	}
+	for name, data := range inputs {
+		structuredInput, err := Parse(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := Part2(ctx, structuredInput)
+		// Up to ranges of a couple of bytes, shorter than a line
+		for _, chunks := range []int{1, 2, 3, 7, 64, len(data) / 3} {
+			got, err := scanPart2(ctx, bytes.NewReader(data), int64(len(data)), chunks, 4)
+			if err != nil || !got.Equal(expected) {
+				t.Errorf("%s, %d chunks: got %s (error %v), expected %s", name, chunks, got, err, expected)
+			}
+		}
+	}
+
+	data := Generate(1000)
+	structuredInput, _ := Parse(data)
+	expected, _ := Part2(ctx, structuredInput)
+	if got, err := ScanPart2(ctx, bytes.NewReader(data), int64(len(data)), 3); err != nil || !got.Equal(expected) {
+		t.Errorf("got %s (error %v), expected %s", got, err, expected)
+	}
+}
+
+// The errors are the errors of Parse, at the same line whatever the range
+func TestScanPart2Errors(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		[]byte("forward 1\n\n"),
+		append(append(Generate(500), "\nback 3\n"...), Generate(500)...),
+		append(append(Generate(500), "\nforward x\n"...), Generate(500)...),
+	} {
+		_, expected := Parse(data)
+		if expected == nil {
+			t.Fatal("expected an error from Parse")
+		}
+		for _, chunks := range []int{1, 5, 64} {
+			_, err := scanPart2(context.Background(), bytes.NewReader(data), int64(len(data)), chunks, 4)
+			var parseErr *aoc.ParseError
+			if !errors.As(err, &parseErr) || err.Error() != expected.Error() {
+				t.Errorf("%d chunks: got %v, expected %v", chunks, err, expected)
+			}
+		}
+	}
+}
+
+func TestScanCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data := Generate(1000)
+	if _, err := scanPart2(ctx, bytes.NewReader(data), int64(len(data)), 4, 4); !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, expected %v", err, context.Canceled)
+	}
+}
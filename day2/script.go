@@ -0,0 +1,636 @@
+package day2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Scripts are a language to write the instructions of the submarine, that
+// compiles down to the up, down and forward instructions of the puzzle:
+//
+//	# Dive in steps
+//	let step = 3
+//	macro dive(depth, distance) {
+//		down depth
+//		forward distance
+//	}
+//	repeat 4 {
+//		dive(step, 2 * step)
+//	}
+//	up 4 * step
+//
+// Expressions are integers, variables, + - * / and parentheses. Macros are
+// defined out of any block, in any order, and see their parameters
+// and the variables of the script. `let` defines or changes a variable of the
+// macro it is in, or of the script. Line breaks are spaces, `#` starts a comment
+
+// maxCallDepth stops a macro that calls itself for ever
+const maxCallDepth = 100
+
+// maxNesting is the deepest blocks and parentheses can nest, the parser and the
+// compiler recurse into them
+const maxNesting = 100
+
+// Pos is where a node of a script starts, line and column start at 1
+type Pos struct {
+	Line, Col int
+}
+
+// Script is a parsed script
+type Script struct {
+	Statements []Statement
+	Macros     map[string]*MacroDef
+}
+
+// Statement is a node of a script that does something: Command, Let, Repeat or Call
+type Statement interface {
+	position() Pos
+}
+
+// Expr is a node of a script that has a value: Number, Var, Unary or Binary
+type Expr interface {
+	position() Pos
+}
+
+// Command is an instruction of the puzzle, `forward 3 * n`
+type Command struct {
+	Pos
+	Word  string
+	Value Expr
+}
+
+// Let sets a variable, `let n = 5`
+type Let struct {
+	Pos
+	Name  string
+	Value Expr
+}
+
+// Repeat runs its body Count times, `repeat 3 { ... }`
+type Repeat struct {
+	Pos
+	Count Expr
+	Body  []Statement
+}
+
+// MacroDef defines a macro, `macro dive(depth, distance) { ... }`
+type MacroDef struct {
+	Pos
+	Name   string
+	Params []string
+	Body   []Statement
+}
+
+// Call runs a macro, `dive(3, 2)`
+type Call struct {
+	Pos
+	Name string
+	Args []Expr
+}
+
+// Number is an integer literal
+type Number struct {
+	Pos
+	Value int
+}
+
+// Var is the value of a variable
+type Var struct {
+	Pos
+	Name string
+}
+
+// Unary is `-x`
+type Unary struct {
+	Pos
+	Op byte
+	X  Expr
+}
+
+// Binary is `x + y`, `x - y`, `x * y` or `x / y`
+type Binary struct {
+	Pos
+	Op          byte
+	Left, Right Expr
+}
+
+func (p Pos) position() Pos { return p }
+
+func (p Pos) errorf(format string, args ...interface{}) error {
+	return &aoc.ParseError{Line: p.Line, Col: p.Col, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Kinds of tokens
+const (
+	tokenEOF = iota
+	tokenIdent
+	tokenNumber
+	// Any of { } ( ) , = + - * /
+	tokenSymbol
+)
+
+type token struct {
+	kind int
+	text string
+	Pos
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of script"
+	}
+	return strconv.Quote(t.text)
+}
+
+// Splits a script in tokens, skipping spaces and comments
+func lex(data []byte) ([]token, error) {
+	tokens := make([]token, 0, len(data)/2)
+	line, col := 1, 1
+	for index := 0; index < len(data); {
+		c := data[index]
+		pos := Pos{line, col}
+		start := index
+		kind := tokenSymbol
+		switch {
+		case c == '\n':
+			index++
+			line, col = line+1, 1
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			index++
+			col++
+			continue
+		case c == '#':
+			for index < len(data) && data[index] != '\n' {
+				index++
+			}
+			continue
+		case isLetter(c):
+			kind = tokenIdent
+			for index < len(data) && (isLetter(data[index]) || isDigit(data[index])) {
+				index++
+			}
+		case isDigit(c):
+			kind = tokenNumber
+			for index < len(data) && isDigit(data[index]) {
+				index++
+			}
+		case strings.IndexByte("{}(),=+-*/", c) >= 0:
+			index++
+		default:
+			return nil, pos.errorf("unexpected character %q", c)
+		}
+		col += index - start
+		tokens = append(tokens, token{kind, string(data[start:index]), pos})
+	}
+	return append(tokens, token{kind: tokenEOF, Pos: Pos{line, col}}), nil
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// Words that can't be the name of a variable or a macro
+var keywords = map[string]bool{"up": true, "down": true, "forward": true, "let": true, "repeat": true, "macro": true}
+
+// Recursive descent parser, one function per rule of the grammar
+type parser struct {
+	tokens []token
+	next   int
+	// Blocks and parentheses the parser is in
+	depth int
+}
+
+// ParseScript reads a script
+func ParseScript(data []byte) (*Script, error) {
+	tokens, err := lex(data)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	script := &Script{Statements: make([]Statement, 0), Macros: make(map[string]*MacroDef)}
+	for p.peek().kind != tokenEOF {
+		if p.peek().text == "macro" {
+			macro, err := p.macro()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := script.Macros[macro.Name]; ok {
+				return nil, macro.errorf("macro %s defined twice", macro.Name)
+			}
+			script.Macros[macro.Name] = macro
+			continue
+		}
+		statement, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		script.Statements = append(script.Statements, statement)
+	}
+	return script, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+// Goes into a block or parentheses, at the token that opens them. leave goes
+// back out
+func (p *parser) enter(t token) error {
+	if p.depth == maxNesting {
+		return t.errorf("too deeply nested, more than %d levels", maxNesting)
+	}
+	p.depth++
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+// Reads a symbol or a keyword
+func (p *parser) expect(text string) (token, error) {
+	t := p.advance()
+	if t.text != text || t.kind == tokenEOF {
+		return t, t.errorf("expected %q, found %s", text, t)
+	}
+	return t, nil
+}
+
+func (p *parser) name(what string) (token, error) {
+	t := p.advance()
+	if t.kind != tokenIdent || keywords[t.text] {
+		return t, t.errorf("expected the name of a %s, found %s", what, t)
+	}
+	return t, nil
+}
+
+func (p *parser) statement() (Statement, error) {
+	t := p.peek()
+	switch {
+	case t.text == "up" || t.text == "down" || t.text == "forward":
+		p.advance()
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &Command{t.Pos, t.text, value}, nil
+	case t.text == "let":
+		p.advance()
+		name, err := p.name("variable")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect("="); err != nil {
+			return nil, err
+		}
+		value, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		return &Let{t.Pos, name.text, value}, nil
+	case t.text == "repeat":
+		p.advance()
+		count, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		body, err := p.block()
+		if err != nil {
+			return nil, err
+		}
+		return &Repeat{t.Pos, count, body}, nil
+	case t.text == "macro":
+		return nil, t.errorf("macros are defined out of any block")
+	case t.kind == tokenIdent:
+		p.advance()
+		if _, err := p.expect("("); err != nil {
+			return nil, err
+		}
+		args := make([]Expr, 0)
+		for p.peek().text != ")" {
+			if len(args) > 0 {
+				if _, err := p.expect(","); err != nil {
+					return nil, err
+				}
+			}
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+		}
+		p.advance()
+		return &Call{t.Pos, t.text, args}, nil
+	}
+	return nil, t.errorf("expected an instruction, let, repeat or a macro, found %s", t)
+}
+
+// Statements between braces
+func (p *parser) block() ([]Statement, error) {
+	open, err := p.expect("{")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.enter(open); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	statements := make([]Statement, 0)
+	for p.peek().text != "}" {
+		if p.peek().kind == tokenEOF {
+			return nil, p.peek().errorf("expected \"}\", found %s", p.peek())
+		}
+		statement, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	p.advance()
+	return statements, nil
+}
+
+func (p *parser) macro() (*MacroDef, error) {
+	t := p.advance()
+	name, err := p.name("macro")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+	params := make([]string, 0)
+	for p.peek().text != ")" {
+		if len(params) > 0 {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		param, err := p.name("parameter")
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, param.text)
+	}
+	p.advance()
+	body, err := p.block()
+	if err != nil {
+		return nil, err
+	}
+	return &MacroDef{t.Pos, name.text, params, body}, nil
+}
+
+// expr = term { ("+" | "-") term }
+func (p *parser) expr() (Expr, error) {
+	left, err := p.term()
+	for err == nil && (p.peek().text == "+" || p.peek().text == "-") {
+		op := p.advance()
+		var right Expr
+		if right, err = p.term(); err == nil {
+			left = &Binary{op.Pos, op.text[0], left, right}
+		}
+	}
+	return left, err
+}
+
+// term = factor { ("*" | "/") factor }
+func (p *parser) term() (Expr, error) {
+	left, err := p.factor()
+	for err == nil && (p.peek().text == "*" || p.peek().text == "/") {
+		op := p.advance()
+		var right Expr
+		if right, err = p.factor(); err == nil {
+			left = &Binary{op.Pos, op.text[0], left, right}
+		}
+	}
+	return left, err
+}
+
+// factor = number | name | "-" factor | "(" expr ")"
+func (p *parser) factor() (Expr, error) {
+	t := p.advance()
+	switch {
+	case t.kind == tokenNumber:
+		value, err := strconv.Atoi(t.text)
+		if err != nil {
+			return nil, &aoc.ParseError{Line: t.Line, Col: t.Col, Msg: "invalid value", Err: err}
+		}
+		return &Number{t.Pos, value}, nil
+	case t.kind == tokenIdent && !keywords[t.text]:
+		return &Var{t.Pos, t.text}, nil
+	case t.text == "-" && t.kind == tokenSymbol:
+		if err := p.enter(t); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return &Unary{t.Pos, '-', x}, nil
+	case t.text == "(" && t.kind == tokenSymbol:
+		if err := p.enter(t); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return x, nil
+	}
+	return nil, t.errorf("expected a value, found %s", t)
+}
+
+// Variables of the script, or of a macro call
+type scope struct {
+	vars   map[string]int
+	parent *scope
+}
+
+func (s *scope) lookup(name string) (int, bool) {
+	for ; s != nil; s = s.parent {
+		if value, ok := s.vars[name]; ok {
+			return value, true
+		}
+	}
+	return 0, false
+}
+
+// Runs a script, the instructions are sent to emit one at a time
+type compiler struct {
+	ctx    context.Context
+	macros map[string]*MacroDef
+	emit   func(Elements) error
+	// Number of instructions emitted, to check ctx
+	emitted int
+	depth   int
+}
+
+// Compile runs the script and returns the instructions of the puzzle it gives
+func (s *Script) Compile(ctx context.Context) ([]Elements, error) {
+	instructions := make([]Elements, 0)
+	err := s.Run(ctx, func(element Elements) error {
+		instructions = append(instructions, element)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return instructions, nil
+}
+
+// Run runs the script and calls emit with every instruction it gives, so
+// scripts of billions of instructions don't have to be held in memory
+func (s *Script) Run(ctx context.Context, emit func(Elements) error) error {
+	c := &compiler{ctx: ctx, macros: s.Macros, emit: emit}
+	return c.run(s.Statements, &scope{vars: make(map[string]int)})
+}
+
+func (c *compiler) run(statements []Statement, vars *scope) error {
+	for _, statement := range statements {
+		if err := c.statement(statement, vars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) statement(statement Statement, vars *scope) error {
+	switch s := statement.(type) {
+	case *Command:
+		value, err := c.eval(s.Value, vars)
+		if err != nil {
+			return err
+		}
+		if err := aoc.Canceled(c.ctx, c.emitted); err != nil {
+			return err
+		}
+		c.emitted++
+		return c.emit(Elements{s.Word, value})
+	case *Let:
+		value, err := c.eval(s.Value, vars)
+		if err != nil {
+			return err
+		}
+		// An existing variable is changed where it is, a new one is local
+		for defined := vars; defined != nil; defined = defined.parent {
+			if _, ok := defined.vars[s.Name]; ok {
+				defined.vars[s.Name] = value
+				return nil
+			}
+		}
+		vars.vars[s.Name] = value
+		return nil
+	case *Repeat:
+		count, err := c.eval(s.Count, vars)
+		if err != nil {
+			return err
+		}
+		if count < 0 {
+			return s.errorf("negative repeat count %d", count)
+		}
+		for i := 0; i < count; i++ {
+			// A loop without instructions would never check ctx otherwise
+			if err := c.ctx.Err(); err != nil {
+				return err
+			}
+			if err := c.run(s.Body, vars); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *Call:
+		macro, ok := c.macros[s.Name]
+		if !ok {
+			return s.errorf("undefined macro %s", s.Name)
+		}
+		if len(s.Args) != len(macro.Params) {
+			return s.errorf("macro %s takes %d arguments, found %d", s.Name, len(macro.Params), len(s.Args))
+		}
+		if c.depth == maxCallDepth {
+			return s.errorf("too many nested calls of macros, more than %d", maxCallDepth)
+		}
+		// Macros calling macros can run for ever without instructions, so every
+		// call checks ctx, as repeat does
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		// The macro sees the variables of the script, not the ones of its caller
+		global := vars
+		for global.parent != nil {
+			global = global.parent
+		}
+		local := &scope{vars: make(map[string]int, len(s.Args)), parent: global}
+		for index, arg := range s.Args {
+			value, err := c.eval(arg, vars)
+			if err != nil {
+				return err
+			}
+			local.vars[macro.Params[index]] = value
+		}
+		c.depth++
+		defer func() { c.depth-- }()
+		return c.run(macro.Body, local)
+	}
+	return statement.position().errorf("unknown statement %T", statement)
+}
+
+func (c *compiler) eval(expr Expr, vars *scope) (int, error) {
+	switch e := expr.(type) {
+	case *Number:
+		return e.Value, nil
+	case *Var:
+		value, ok := vars.lookup(e.Name)
+		if !ok {
+			return 0, e.errorf("undefined variable %s", e.Name)
+		}
+		return value, nil
+	case *Unary:
+		x, err := c.eval(e.X, vars)
+		return -x, err
+	case *Binary:
+		left, err := c.eval(e.Left, vars)
+		if err != nil {
+			return 0, err
+		}
+		right, err := c.eval(e.Right, vars)
+		if err != nil {
+			return 0, err
+		}
+		switch e.Op {
+		case '+':
+			return left + right, nil
+		case '-':
+			return left - right, nil
+		case '*':
+			return left * right, nil
+		case '/':
+			if right == 0 {
+				return 0, e.errorf("division by zero")
+			}
+			return left / right, nil
+		}
+	}
+	return 0, expr.position().errorf("unknown expression %T", expr)
+}
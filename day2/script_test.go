@@ -0,0 +1,149 @@
+package day2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Compiles a script to the lines of an input
+func compile(t *testing.T, script string) (string, error) {
+	t.Helper()
+	s, err := ParseScript([]byte(script))
+	if err != nil {
+		return "", err
+	}
+	instructions, err := s.Compile(context.Background())
+	if err != nil {
+		return "", err
+	}
+	lines := make([]string, len(instructions))
+	for index, instruction := range instructions {
+		lines[index] = instruction.String()
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func TestScript(t *testing.T) {
+	for _, test := range []struct {
+		name, script, expected string
+	}{
+		{"instructions", "forward 5\ndown 5 up 3", "forward 5\ndown 5\nup 3"},
+		{"expressions", "let n = 4\nforward n * (2 + 1) - 10 / 5\ndown -n", "forward 10\ndown -4"},
+		{"comments", "# start\nforward 1 # one\n\n# end", "forward 1"},
+		{"repeat", "repeat 2 { forward 1 repeat 2 { down 1 } }", "forward 1\ndown 1\ndown 1\nforward 1\ndown 1\ndown 1"},
+		{"let in repeat", "let d = 1 repeat 3 { down d let d = d * 2 }", "down 1\ndown 2\ndown 4"},
+		{"macro", "dive(2, 3)\nmacro dive(depth, distance) { down depth forward distance }", "down 2\nforward 3"},
+		{"macro globals", "let step = 2 macro go() { forward step let step = step + 1 } go() go()", "forward 2\nforward 3"},
+		{"macro locals", "macro a(x) { b(x + 1) forward x } macro b(y) { down y } a(1)", "down 2\nforward 1"},
+		{"nothing", "repeat 0 { forward 1 }", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := compile(t, test.script)
+			if err != nil || got != test.expected {
+				t.Errorf("got %q, %v, expected %q", got, err, test.expected)
+			}
+		})
+	}
+}
+
+// The example of the puzzle, written as a script
+func TestScriptExample(t *testing.T) {
+	script := `
+		macro step(forward_by, down_by) {
+			forward forward_by
+			down down_by
+		}
+		step(5, 5)
+		step(8, -3)   # up 3, as a negative down
+		down 8
+		forward 2
+	`
+	s, err := ParseScript([]byte(script))
+	if err != nil {
+		t.Fatal(err)
+	}
+	instructions, err := s.Compile(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	answer, err := Part2(context.Background(), instructions)
+	if err != nil || !answer.Equal(aoc.Int(900)) {
+		t.Errorf("got %s, %v, expected 900", answer, err)
+	}
+}
+
+func TestScriptErrors(t *testing.T) {
+	for _, test := range []struct {
+		script    string
+		line, col int
+	}{
+		{"forward 1\nforward $", 2, 9},
+		{"forward", 1, 8},
+		{"let up = 3", 1, 5},
+		{"let x 3", 1, 7},
+		{"repeat 2 { forward 1", 1, 21},
+		{"repeat 2 {\n  macro m() { }\n}", 2, 3},
+		{"macro m() { } macro m() { }", 1, 15},
+		{"jump 3", 1, 6},
+		{"forward 99999999999999999999", 1, 9},
+		{"forward x", 1, 9},
+		{"forward 1 / (2 - 2)", 1, 11},
+		{"repeat -1 { }", 1, 1},
+		{"m(1)", 1, 1},
+		{"macro m(x) { } m()", 1, 16},
+		{"macro m() { m() } m()", 1, 13},
+		{strings.Repeat("repeat 1 { ", 101), 1, 1110},
+		{"forward " + strings.Repeat("(", 101) + "1", 1, 109},
+		{"forward " + strings.Repeat("-", 101) + "1", 1, 109},
+	} {
+		_, err := compile(t, test.script)
+		var parseErr *aoc.ParseError
+		if !errors.As(err, &parseErr) || parseErr.Line != test.line || parseErr.Col != test.col {
+			t.Errorf("%.40q: got %v, expected an error at line %d, column %d", test.script, err, test.line, test.col)
+		}
+	}
+}
+
+func TestScriptCanceled(t *testing.T) {
+	// Macros calling the next one twice, 2^40 calls and no instruction
+	macros := "macro m0() { }"
+	for i := 1; i <= 40; i++ {
+		macros += fmt.Sprintf(" macro m%d() { m%d() m%d() }", i, i-1, i-1)
+	}
+	for _, script := range []string{
+		"repeat 1000000000 { repeat 1000000000 { } forward 1 }",
+		macros + " m40()",
+	} {
+		s, err := ParseScript([]byte(script))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := s.Compile(ctx); !errors.Is(err, context.Canceled) {
+			t.Errorf("%.40q: got %v, expected %v", script, err, context.Canceled)
+		}
+	}
+}
+
+func TestScriptRun(t *testing.T) {
+	s, _ := ParseScript([]byte("forward 1 forward 2 forward 3"))
+	stop := errors.New("stop")
+	got := make([]Elements, 0)
+	err := s.Run(context.Background(), func(e Elements) error {
+		got = append(got, e)
+		if len(got) == 2 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || !reflect.DeepEqual(got, []Elements{{"forward", 1}, {"forward", 2}}) {
+		t.Errorf("got %v, %v, expected the first 2 instructions and the error of emit", got, err)
+	}
+}
@@ -0,0 +1,111 @@
+package day2
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// Size of the chart of the path of the submarine
+const (
+	chartWidth  = 60
+	chartHeight = 15
+)
+
+// Visualize moves the submarine one instruction at a time, the way Part2 does,
+// drawing its path with the horizontal position across and the depth down
+func Visualize(ctx context.Context, structuredInput []Elements, show func(frame string) error) error {
+	// The whole path is needed first, to fit it in the chart
+	path := make([]Position, 0, len(structuredInput)+1)
+	position := Position{}
+	path = append(path, position)
+	for index, element := range structuredInput {
+		switch element.word {
+		case "down":
+			position.aim += element.value
+		case "up":
+			position.aim -= element.value
+		case "forward":
+			position.horizontal += element.value
+			position.depth += position.aim * element.value
+		default:
+			return fmt.Errorf("instruction %d: %w: %q", index+1, ErrUnknownInstruction, element.word)
+		}
+		path = append(path, position)
+	}
+	minDepth, maxDepth, maxHorizontal := 0, 0, 0
+	for _, p := range path {
+		if p.depth < minDepth {
+			minDepth = p.depth
+		}
+		if p.depth > maxDepth {
+			maxDepth = p.depth
+		}
+		if p.horizontal > maxHorizontal {
+			maxHorizontal = p.horizontal
+		}
+	}
+
+	chart := make([][]bool, chartHeight)
+	for row := range chart {
+		chart[row] = make([]bool, chartWidth)
+	}
+	x, y := 0, visual.Scale(0, minDepth, maxDepth, chartHeight)
+	for index, current := range path {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Trace the line from the previous position, one character at a time
+		previousX, previousY := x, y
+		x, y = visual.Scale(current.horizontal, 0, maxHorizontal, chartWidth), visual.Scale(current.depth, minDepth, maxDepth, chartHeight)
+		steps := abs(x - previousX)
+		if abs(y-previousY) > steps {
+			steps = abs(y - previousY)
+		}
+		for step := 0; step <= steps; step++ {
+			if steps == 0 {
+				chart[y][x] = true
+				break
+			}
+			chart[previousY+(y-previousY)*step/steps][previousX+(x-previousX)*step/steps] = true
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s - instruction %d of %d", visual.Bold("Day 2"), index, len(structuredInput))
+		if index > 0 {
+			element := structuredInput[index-1]
+			fmt.Fprintf(&b, ": %s", visual.Highlight(fmt.Sprintf("%s %d", element.word, element.value)))
+		}
+		fmt.Fprintf(&b, "\n\nHorizontal %d   Depth %d   Aim %d\n\n", current.horizontal, current.depth, current.aim)
+		for row, cells := range chart {
+			b.WriteString("|")
+			for col, visited := range cells {
+				switch {
+				case row == y && col == x:
+					b.WriteString(visual.Highlight(">"))
+				case visited:
+					b.WriteString(".")
+				default:
+					b.WriteString(" ")
+				}
+			}
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "+%s\n", strings.Repeat("-", chartWidth))
+		fmt.Fprintf(&b, "\nDepth x horizontal: %s", visual.Win(aoc.Product(current.depth, current.horizontal).String()))
+		if err := show(b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
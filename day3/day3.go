@@ -0,0 +1,253 @@
+package day3
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+var (
+	// ErrNoInput is returned when there is no number left to find a rating from
+	ErrNoInput = errors.New("no input provided to getRating")
+	// ErrIndexOutOfBounds is returned when all the bits have been checked
+	// and there is still more than one number left
+	ErrIndexOutOfBounds = errors.New("index out of bounds in getRating")
+)
+
+// Numbers are at most that many bits long, so their values fit in an int
+const maxBits = 63
+
+type Rate struct {
+	value []bool
+}
+
+//go:embed *.txt
+var files embed.FS
+
+func init() {
+	aoc.Register(aoc.Day{
+		Number: 3,
+		Parse:  func(data []byte) (interface{}, error) { return Parse(data) },
+		Part1: []aoc.Solution{
+			{Name: "counts", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part1(ctx, p.([]Rate)) }},
+		},
+		Part2: []aoc.Solution{
+			{Name: "recursive", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part2(ctx, p.([]Rate)) }},
+			{Name: "in-place", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return part2InPlace(ctx, p.([]Rate)) }},
+		},
+		Generate: Generate,
+		Visualize: func(ctx context.Context, p interface{}, show func(string) error) error {
+			return Visualize(ctx, p.([]Rate), show)
+		},
+		Files: files,
+	})
+}
+
+// Part 1: multiply the gamma rate by the epsilon rate
+func Part1(ctx context.Context, structuredInput []Rate) (aoc.Answer, error) {
+	// sumsOfOnes will contain the count of '1' at each index over the whole input
+	sumsOfOnes, err := getCountsOfOnes(ctx, structuredInput)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+	// Now, to find Gamma and Epsilon rates, we need to verify whether each value
+	//  in sumsOfOnes is more or less than half the number of inputs
+	gammaRate := 0
+	epsilonRate := 0
+	for index, count := range sumsOfOnes {
+		if count > (len(structuredInput) / 2) {
+			gammaRate += 1 << (len(sumsOfOnes) - index - 1)
+		} else {
+			epsilonRate += 1 << (len(sumsOfOnes) - index - 1)
+		}
+	}
+	return aoc.Product(gammaRate, epsilonRate), nil
+}
+
+// Part 2: multiply th oxygen generator rating by the CO2 scrubber rating = life support rating
+func Part2(ctx context.Context, structuredInput []Rate) (aoc.Answer, error) {
+	// Calculate oxygen rate
+	oRate, err := getRating(ctx, structuredInput, true, 0)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+
+	// Calculate CO2 rate
+	co2RateStruct, err := getRating(ctx, structuredInput, false, 0)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+
+	return aoc.Product(toInt(oRate), toInt(co2RateStruct)), nil
+}
+
+// Returns the integer value of the binary number held by a rate
+func toInt(rate Rate) int {
+	result := 0
+	for index, value := range rate.value {
+		if value {
+			result += 1 << (len(rate.value) - index - 1)
+		}
+	}
+	return result
+}
+
+// Parse reads the input file. It contains a list of binary numbers
+// From the input, all elements are 12 bits long, but at least they must all have the same length,
+// and no more than maxBits
+func Parse(file []byte) ([]Rate, error) {
+	digits, err := input.Digits(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// When knowing the size, it's better to allocate the right size immediately
+	// as append() has a cost
+	// https://medium.com/vendasta/golang-the-time-complexity-of-append-2177dcfb6bad
+	// /!\ Do not use make([]Rate, len(digits)) as it will give it cap AND size len(digits), and
+	// appending to it will just append after the last element, so the first elements will be 0
+	structuredInput := make([]Rate, 0, len(digits))
+
+	for lineIndex, line := range digits {
+		if len(line) > maxBits {
+			return nil, &aoc.ParseError{Line: lineIndex + 1, Col: maxBits + 1, Msg: fmt.Sprintf("number of %d bits, expected %d bits at most", len(line), maxBits)}
+		}
+		boolArr := make([]bool, 0, len(line))
+		// Get the integer value from the line
+		for index, digit := range line {
+			if digit > 1 {
+				return nil, &aoc.ParseError{Line: lineIndex + 1, Col: index + 1, Msg: fmt.Sprintf("unexpected digit %d, expected 0 or 1", digit)}
+			}
+			boolArr = append(boolArr, digit == 1)
+		}
+		structuredInput = append(structuredInput, Rate{boolArr})
+	}
+
+	return structuredInput, nil
+}
+
+func getCountsOfOnes(ctx context.Context, structuredInput []Rate) ([]int, error) {
+	if len(structuredInput) == 0 {
+		return nil, nil
+	}
+	sumsOfOnes := make([]int, len(structuredInput[0].value))
+	for rateIndex, rate := range structuredInput {
+		if err := aoc.Canceled(ctx, rateIndex); err != nil {
+			return nil, err
+		}
+		for index, zeroOrOne := range rate.value {
+			if zeroOrOne {
+				sumsOfOnes[index] += 1
+			}
+		}
+	}
+	return sumsOfOnes, nil
+}
+
+// input: a list of rates (the input from the exercise, successively filtered)
+// defaultKeep: is used to define which rates should be kept in case the
+// counts of 1 and 0 at the given index for the given input are equal
+// To find the oxygen rate, use defaultKeep = 1, to find the CO2 rate, use defaultKeep = 0
+// index: the bit index to check in the given rates
+func getRating(ctx context.Context, input []Rate, defaultKeep bool, index int) (Rate, error) {
+	// No input
+	if len(input) == 0 {
+		return Rate{nil}, ErrNoInput
+	}
+
+	// All the bits were checked and several rates are left: they are all the same
+	if index >= len(input[0].value) {
+		return Rate{nil}, ErrIndexOutOfBounds
+	}
+
+	//Get the counts of '1' at each position for the given input
+	sumsOfOnes, err := getCountsOfOnes(ctx, input)
+	if err != nil {
+		return Rate{nil}, err
+	}
+
+	// Should we keep numbers in 0 or 1?
+	keep := defaultKeep
+	if float32(sumsOfOnes[index]) > (float32(len(input)) / 2) {
+		// Most common value is 1
+		keep = defaultKeep
+	} else if float32(sumsOfOnes[index]) < (float32(len(input)) / 2) {
+		// Most common value is 0
+		keep = !defaultKeep
+	}
+
+	newInput := make([]Rate, 0, len(input))
+	for _, rate := range input {
+		if rate.value[index] == keep {
+			newInput = append(newInput, rate)
+		}
+	}
+	if len(newInput) == 1 {
+		return newInput[0], nil
+	} else {
+		return getRating(ctx, newInput, defaultKeep, index+1)
+	}
+}
+
+// Same as Part2, but the candidates are filtered in a single buffer instead of
+// allocating a new slice and recounting all the bits at every step
+func part2InPlace(ctx context.Context, structuredInput []Rate) (aoc.Answer, error) {
+	candidates := make([]Rate, len(structuredInput))
+	oRate, err := getRatingInPlace(ctx, structuredInput, candidates, true)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+	co2Rate, err := getRatingInPlace(ctx, structuredInput, candidates, false)
+	if err != nil {
+		return aoc.Answer{}, err
+	}
+	return aoc.Product(toInt(oRate), toInt(co2Rate)), nil
+}
+
+// Same as getRating. candidates is a buffer the size of input used to
+// keep the rates that are still in the race
+func getRatingInPlace(ctx context.Context, input []Rate, candidates []Rate, defaultKeep bool) (Rate, error) {
+	if len(input) == 0 {
+		return Rate{nil}, ErrNoInput
+	}
+	candidates = candidates[:len(input)]
+	copy(candidates, input)
+
+	for index := 0; len(candidates) != 1; index++ {
+		if len(candidates) == 0 {
+			return Rate{nil}, ErrNoInput
+		}
+		if index >= len(candidates[0].value) {
+			return Rate{nil}, ErrIndexOutOfBounds
+		}
+		if err := ctx.Err(); err != nil {
+			return Rate{nil}, err
+		}
+		// Only the bit at index matters, no need to count the others
+		ones := 0
+		for _, rate := range candidates {
+			if rate.value[index] {
+				ones++
+			}
+		}
+		keep := defaultKeep
+		if 2*ones < len(candidates) {
+			keep = !defaultKeep
+		}
+
+		// Keep the matching rates at the beginning of the buffer
+		kept := 0
+		for _, rate := range candidates {
+			if rate.value[index] == keep {
+				candidates[kept] = rate
+				kept++
+			}
+		}
+		candidates = candidates[:kept]
+	}
+	return candidates[0], nil
+}
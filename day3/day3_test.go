@@ -0,0 +1,90 @@
+package day3
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/aoc/aoctest"
+)
+
+// The examples given in the puzzle, with their answers
+var examples = []aoctest.Example{
+	{File: "example.txt", Part1: 198, Part2: 230},
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.CheckExamples(t, 3, examples)
+}
+
+func TestSolutionsAgree(t *testing.T) {
+	aoctest.CheckSolutions(t, 3, Generate(1000))
+}
+
+func TestCanceled(t *testing.T) {
+	aoctest.CheckCanceled(t, 3, Generate(1000))
+}
+
+func TestVisualize(t *testing.T) {
+	aoctest.CheckVisualize(t, 3, Generate(100))
+}
+
+// Numbers of 63 bits are the widest whose values fit in an int
+func TestWidth(t *testing.T) {
+	wide := strings.Repeat("1", 63) + "\n" + strings.Repeat("0", 63) + "\n" + strings.Repeat("1", 63)
+	rates, err := Parse([]byte(wide))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if answer, err := Part1(context.Background(), rates); err != nil || !answer.Equal(aoc.Int(0)) {
+		t.Errorf("got %s, %v, expected 0", answer, err)
+	}
+	aoctest.CheckSolutions(t, 3, []byte(wide))
+
+	_, err = Parse([]byte(strings.Repeat("1", 64) + "\n" + strings.Repeat("0", 64)))
+	var parseErr *aoc.ParseError
+	if !errors.As(err, &parseErr) || parseErr.Line != 1 || parseErr.Col != 64 {
+		t.Errorf("got %v, expected an error at line 1, column 64", err)
+	}
+}
+
+// Number of binary numbers in the synthetic input used by the benchmarks
+// It is well above the size of the real input
+const benchSize = 100000
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, 3, Generate(benchSize))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, 3, 1, Generate(benchSize))
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, 3, 2, Generate(benchSize))
+}
+
+func FuzzParse(f *testing.F) {
+	for _, example := range examples {
+		data, err := files.ReadFile(example.File)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(Generate(10))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		structuredInput, err := Parse(data)
+		if err != nil {
+			return
+		}
+		// The parts count on all the numbers having the same number of bits
+		for _, rate := range structuredInput {
+			if len(rate.value) != len(structuredInput[0].value) {
+				t.Fatalf("got numbers of %d and %d bits", len(structuredInput[0].value), len(rate.value))
+			}
+		}
+	})
+}
@@ -0,0 +1,33 @@
+package day3
+
+import (
+	"math/rand"
+)
+
+// Generate returns a synthetic input of n binary numbers
+// The numbers are all different, otherwise part 2 could never narrow the
+// candidates down to a single one. They are 12 bits long like in the real
+// inputs, or longer when 12 bits are not enough for n different numbers
+func Generate(n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	width := 12
+	for 1<<width < 2*n {
+		width++
+	}
+	data := make([]byte, 0, n*(width+1))
+	seen := make(map[int]bool, n)
+	for i := 0; i < n; i++ {
+		value := r.Intn(1 << width)
+		for seen[value] {
+			value = r.Intn(1 << width)
+		}
+		seen[value] = true
+		if i > 0 {
+			data = append(data, '\n')
+		}
+		for bit := width - 1; bit >= 0; bit-- {
+			data = append(data, byte('0'+(value>>bit)&1))
+		}
+	}
+	return data
+}
@@ -0,0 +1,100 @@
+package day3
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// Number of candidates shown in a frame, the others are counted
+const visibleCandidates = 20
+
+// Visualize shrinks the candidates of each rating of Part2 bit by bit, showing
+// the bit they are filtered on and how many ones and zeros it has
+func Visualize(ctx context.Context, structuredInput []Rate, show func(frame string) error) error {
+	ratings := []struct {
+		name        string
+		defaultKeep bool
+	}{
+		{"Oxygen generator rating", true},
+		{"CO2 scrubber rating", false},
+	}
+	for _, rating := range ratings {
+		candidates := structuredInput
+		for index := 0; len(candidates) > 1 && index < len(candidates[0].value); index++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			ones := 0
+			for _, rate := range candidates {
+				if rate.value[index] {
+					ones++
+				}
+			}
+			// Same rule as getRating
+			keep := rating.defaultKeep
+			if 2*ones < len(candidates) {
+				keep = !rating.defaultKeep
+			}
+
+			frame := drawCandidates(rating.name, candidates, index)
+			frame += fmt.Sprintf("\n%d ones, %d zeros: keeping the %s", ones, len(candidates)-ones, visual.Highlight(bit(keep)+"s"))
+			if err := show(frame); err != nil {
+				return err
+			}
+
+			kept := make([]Rate, 0, len(candidates))
+			for _, rate := range candidates {
+				if rate.value[index] == keep {
+					kept = append(kept, rate)
+				}
+			}
+			candidates = kept
+		}
+
+		frame := drawCandidates(rating.name, candidates, -1)
+		if len(candidates) == 1 {
+			frame += fmt.Sprintf("\n%s: %s", rating.name, visual.Win(fmt.Sprint(toInt(candidates[0]))))
+		} else {
+			frame += fmt.Sprintf("\n%d candidates left, no %s", len(candidates), rating.name)
+		}
+		if err := show(frame); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Draws the candidates with the bits already filtered on dimmed, and the one
+// being filtered on highlighted
+func drawCandidates(name string, candidates []Rate, index int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s - %s, %d candidates\n\n", visual.Bold("Day 3"), name, len(candidates))
+	for count, rate := range candidates {
+		if count == visibleCandidates {
+			fmt.Fprintf(&b, "... and %d more\n", len(candidates)-visibleCandidates)
+			break
+		}
+		for i, value := range rate.value {
+			switch {
+			case i < index:
+				b.WriteString(visual.Faint(bit(value)))
+			case i == index:
+				b.WriteString(visual.Highlight(bit(value)))
+			default:
+				b.WriteString(bit(value))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func bit(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
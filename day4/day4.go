@@ -1,132 +1,161 @@
-package main
+package day4
 
 import (
+	"context"
+	"embed"
 	"errors"
-	"log"
-	"os"
-	"regexp"
-	"strconv"
-	"strings"
+	"fmt"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+var (
+	// ErrNoWinner is returned when all the numbers have been drawn and no grid won
+	ErrNoWinner = errors.New("no winner")
+	// ErrMultipleRemainingGrids is returned when all the numbers have been drawn
+	// and more than one grid did not win
+	ErrMultipleRemainingGrids = errors.New("multiple remaining grids")
 )
 
 type SumAndCount struct {
-	sum int
+	sum   int
 	count int
 }
 
-func main() {
-	// Play bingo
-	// A grid is a structure of 5 lines, each containing 5 numbers
-	// A grid is the winning grid if one of the lines had all its numbers drawn
+// Play bingo
+// A grid is a structure of 5 lines, each containing 5 numbers
+// A grid is the winning grid if one of the lines had all its numbers drawn
+//
+// To play, we use 5 structures:
+// 1. A list (a queue) of the drawn numbers, in the order they were drawn
+// 2. An array of integers whose:
+//   - indexes represent the row number in the grids
+//   - values are the sum of all numbers on that row and the count of numbers drawn on that row
+//     Rows in grid 1 are at indexes 0 to 4, rows in grid 2 are at indexes 5 to 9, etc
+//
+// 3. A map of <integer,integer> whose
+//   - keys are numbers presents in the grids' rows
+//   - value for a key is a list of the rows where that number is present (indexes in the previous structure)
+//
+// 4. An array of integers whose:
+//   - indexes represent the grid and column number in the grids
+//   - values are the sum of all numbers on that column for that grid and the count of numbers drawn on that column
+//     Columns in grid 1 are at indexes 0 to 4, columns in grid 2 are at indexes 5 to 9, etc
+//
+// 5. A map of <integer,integer> whose
+//   - keys are numbers presents in the grids' columns
+//   - value for a key is a list of the columns where that number is present (indexes in the previous structure)
+type Game struct {
+	drawnNumbers    []int
+	rowSums         []SumAndCount
+	rowReverseIndex map[int][]int
+	colSums         []SumAndCount
+	colReverseIndex map[int][]int
+}
+
+//go:embed *.txt
+var files embed.FS
 
-	// Part 1: we'll create 5 structures:
-	// 1. A list (a queue) of the drawn numbers, in the order they were drawn
-	// 2. An array of integers whose:
-	//    * indexes represent the row number in the grids
-	//    * values are the sum of all numbers on that row and the count of numbers drawn on that row
-	//    Rows in grid 1 are at indexes 0 to 4, rows in grid 2 are at indexes 5 to 9, etc
-	// 3. A map of <integer,integer> whose
-	//    * keys are numbers presents in the grids' rows
-	//    * value for a key is a list of the rows where that number is present (indexes in the previous structure)
-	// 4. An array of integers whose:
-	//    * indexes represent the grid and column number in the grids
-	//    * values are the sum of all numbers on that column for that grid and the count of numbers drawn on that column
-	//    Columns in grid 1 are at indexes 0 to 4, columns in grid 2 are at indexes 5 to 9, etc
-	// 5. A map of <integer,integer> whose
-	//    * keys are numbers presents in the grids' columns
-	//    * value for a key is a list of the columns where that number is present (indexes in the previous structure)
-	drawnNumbers, rowSums, rowReverseIndex, colSums, colReverseIndex, err := getStructFromInput("day4/input.txt")
-	if err != nil {
-		log.Fatal(err)
-	}
+func init() {
+	aoc.Register(aoc.Day{
+		Number: 4,
+		Parse:  func(data []byte) (interface{}, error) { return Parse(data) },
+		Part1: []aoc.Solution{
+			{Name: "reverse-index", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part1(ctx, p.(Game)) }},
+		},
+		Part2: []aoc.Solution{
+			{Name: "reverse-index", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) { return Part2(ctx, p.(Game)) }},
+		},
+		Generate: Generate,
+		Visualize: func(ctx context.Context, p interface{}, show func(string) error) error {
+			return Visualize(ctx, p.(Game), show)
+		},
+		Files: files,
+	})
+}
 
-	result, winningDrawIndex, err := processPart1(drawnNumbers, rowSums, rowReverseIndex, colSums, colReverseIndex)
+// Playing decreases the sums and counts, so every part plays on its own copy of them
+func (g Game) sums() ([]SumAndCount, []SumAndCount) {
+	rowSums := make([]SumAndCount, len(g.rowSums))
+	copy(rowSums, g.rowSums)
+	colSums := make([]SumAndCount, len(g.colSums))
+	copy(colSums, g.colSums)
+	return rowSums, colSums
+}
+
+// Part 1: the score of the first grid to win
+func Part1(ctx context.Context, g Game) (aoc.Answer, error) {
+	rowSums, colSums := g.sums()
+	result, _, err := processPart1(ctx, g.drawnNumbers, rowSums, g.rowReverseIndex, colSums, g.colReverseIndex)
+	return result, err
+}
+
+// Part 2: we play until our last grid wins. For that we need to keep the number of winning grids
+// We'll actually keep a count of grids that did not win
+func Part2(ctx context.Context, g Game) (aoc.Answer, error) {
+	// We first play until the first grid wins, as in part 1
+	rowSums, colSums := g.sums()
+	_, winningDrawIndex, err := processPart1(ctx, g.drawnNumbers, rowSums, g.rowReverseIndex, colSums, g.colReverseIndex)
 	if err != nil {
-		log.Printf("Part 1 - %s", err)
-	} else {
-		log.Printf("Part 1 - %d", result)
+		return aoc.Answer{}, err
 	}
 
-	// Part 2: we play until our last grid wins. For that we need to keep the number of winning grids
-	// We'll actually keep a count of grids that did not win
 	remainingGrids := countRemainingNonWinningGrids(rowSums, colSums)
-	result, err = processPart2(drawnNumbers, rowSums, rowReverseIndex, colSums, colReverseIndex, remainingGrids, winningDrawIndex)
-	if err != nil {
-		log.Printf("Part 2 - %s", err)
-	} else {
-		log.Printf("Part 2 - %d", result)
-	}
+	return processPart2(ctx, g.drawnNumbers, rowSums, g.rowReverseIndex, colSums, g.colReverseIndex, remainingGrids, winningDrawIndex)
 }
 
-// See in main the 5 structures that this function will return
-// It returns the 5 structures mentioned in main in the order mentioned:
-// * the list of drawn numbers
-// * the sums for each row in every grid
-// * the reverse index for each number in the grids and which row they can be found in
-// * the sums for each column in every grid
-// * the reverse index for each number in the grids and which column they can be found in
-func getStructFromInput(path string) (
-	[]int,
-	[]SumAndCount,
-	map[int][]int,
-	[]SumAndCount,
-	map[int][]int,
-	error) {
+// Parse reads the input file and returns the 5 structures of the game, see Game
+func Parse(file []byte) (Game, error) {
 	// Read the input file. It contains
 	// * A first line with a series of number in order in which they were drawn. separated by `,`
 	// * A series of 5 consecutive lines with each 5 numbers seperated by spaces
 	// * the series of 5 lines are separated by an empty line
-
-	file, err := os.ReadFile(path)
-	if err != nil {
-		return nil, nil, nil, nil, nil, err
+	blocks := input.Blocks(file)
+	if len(blocks) < 2 {
+		return Game{}, &aoc.ParseError{Line: 1, Col: 1, Msg: "no bingo grid in input file"}
 	}
-	lines:=strings.Split(string(file), "\n") // lines in the file
 
-	drawnNumbers := make([]int,0)
 	rowReverseIndex := make(map[int][]int)
-	rowSums := make([]SumAndCount, 0)
+	rowSums := make([]SumAndCount, 0, (len(blocks)-1)*5)
 	colReverseIndex := make(map[int][]int)
-	colSums := make([]SumAndCount, 0)
+	colSums := make([]SumAndCount, 0, (len(blocks)-1)*5)
 
 	// Process the first line that contains the drawn numbers
-	firstLine := lines[0] // line in the file
-	lines = lines[2:] // discard that first line and the next empty line
-	for _ , number := range strings.Split(firstLine, ",") {
-		value, err := strconv.Atoi(number)
-		if err != nil {
-			return nil, nil, nil, nil, nil, err
-		}
-		drawnNumbers = append(drawnNumbers, value)
+	if len(blocks[0].Lines) != 1 {
+		return Game{}, &aoc.ParseError{Line: 2, Col: 1, Msg: "expected an empty line after the drawn numbers"}
+	}
+	drawnNumbers, err := input.CommaInts(blocks[0].Lines[0], blocks[0].Line)
+	if err != nil {
+		return Game{}, err
 	}
 
-	// Process the other lines that contains the bingo grid lines
-	lineIndex := 0 // Can't use the range index as it would include empty lines in between grids
-	for _ , line := range lines {
-		// each line contains numbers split by a whitespace
-		// except empty lines in between bingo grids
-		if len(line) != 0 {
-			re := regexp.MustCompile("\\s+")
-			line = strings.TrimSpace(line) // remove leading and trailing white space, because re.Split does not
-			numbers:=re.Split(line, -1)
-			rowSums = append(rowSums,SumAndCount{0, 0})
-			for colIndex , number := range numbers {
+	// Process the other blocks that contains the bingo grids
+	lineIndex := 0 // Index of the row among the rows of all the grids
+	for _, block := range blocks[1:] {
+		if len(block.Lines) != 5 {
+			return Game{}, &aoc.ParseError{Line: block.Line, Col: 1, Msg: fmt.Sprintf("expected 5 rows in a grid, found %d", len(block.Lines))}
+		}
+		for index, line := range block.Lines {
+			// each line contains numbers split by a whitespace
+			numbers, err := input.FieldInts(line, block.Line+index)
+			if err != nil {
+				return Game{}, err
+			}
+			if len(numbers) != 5 {
+				return Game{}, &aoc.ParseError{Line: block.Line + index, Col: 1, Msg: fmt.Sprintf("expected 5 numbers in a grid row, found %d", len(numbers))}
+			}
+			rowSums = append(rowSums, SumAndCount{0, 0})
+			for colIndex, value := range numbers {
 				if lineIndex%5 == 0 { // for the 1st time we encounter a new column in this grid
 					colSums = append(colSums, SumAndCount{0, 0})
 				}
-				value, err := strconv.Atoi(number)
-				if err != nil {
-					return nil, nil, nil, nil, nil, err
-				}
 
 				// Process row
 				rowSumAndCount := rowSums[lineIndex]
 				rowSumAndCount.sum += value
 				rowSumAndCount.count += 1
 				rowSums[lineIndex] = rowSumAndCount
-				if rowReverseIndex[value] == nil {
-					rowReverseIndex[value] = make([]int, 0)
-				}
 				rowReverseIndex[value] = append(rowReverseIndex[value], lineIndex)
 
 				// Process column
@@ -134,16 +163,13 @@ func getStructFromInput(path string) (
 				colSumAndCount.sum += value
 				colSumAndCount.count++
 				colSums[((lineIndex/5)*5)+colIndex] = colSumAndCount
-				if colReverseIndex[value] == nil {
-					colReverseIndex[value] = make([]int, 0)
-				}
 				colReverseIndex[value] = append(colReverseIndex[value], ((lineIndex/5)*5)+colIndex)
 			}
 			lineIndex++
 		}
 	}
 
-	return drawnNumbers, rowSums, rowReverseIndex, colSums, colReverseIndex, nil
+	return Game{drawnNumbers, rowSums, rowReverseIndex, colSums, colReverseIndex}, nil
 }
 
 // Part 1: Find the winning grid. Then sum the rest of the numbers that were
@@ -158,15 +184,19 @@ func getStructFromInput(path string) (
 // value from that and the next 4 lines.
 // It also returns the index of the winning number
 func processPart1(
+	ctx context.Context,
 	drawnNumbers []int,
 	rowSums []SumAndCount,
 	rowReverseIndex map[int][]int,
 	colSums []SumAndCount,
-	colReverseIndex map[int][]int) (int, int, error) {
+	colReverseIndex map[int][]int) (aoc.Answer, int, error) {
 	// Processing the drawn number 1 by 1
-	for index , draw := range drawnNumbers {
+	for index, draw := range drawnNumbers {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return aoc.Answer{}, 0, err
+		}
 		// For each drawn number, we look in the rowReverseIndex map in which row we'll find them
-		for _ , gridLine := range rowReverseIndex[draw] {
+		for _, gridLine := range rowReverseIndex[draw] {
 			sumAndCount := rowSums[gridLine]
 			sumAndCount.sum -= draw
 			sumAndCount.count--
@@ -174,7 +204,7 @@ func processPart1(
 			// Check if we have a winner
 			if sumAndCount.count == 0 {
 				// We process the columns as well to keep it consistent, before we return
-				for _ , gridCol := range colReverseIndex[draw] {
+				for _, gridCol := range colReverseIndex[draw] {
 					sumAndCount := colSums[gridCol]
 					sumAndCount.sum -= draw
 					sumAndCount.count--
@@ -185,7 +215,7 @@ func processPart1(
 		}
 
 		// For each drawn number, we look in the colReverseIndex map in which column we'll find them
-		for _ , gridCol := range colReverseIndex[draw] {
+		for _, gridCol := range colReverseIndex[draw] {
 			sumAndCount := colSums[gridCol]
 			sumAndCount.sum -= draw
 			sumAndCount.count--
@@ -198,34 +228,38 @@ func processPart1(
 	}
 
 	// No winner --> return an error
-	return 0, 0, errors.New("no winner")
+	return aoc.Answer{}, 0, ErrNoWinner
 }
 
 // Return the multiplication of the winning number by the sum of the remaining values in the same grid
-func weHaveAWinner(winningDrawNumber int, lineIndex int, lineSums []SumAndCount) int {
+func weHaveAWinner(winningDrawNumber int, lineIndex int, lineSums []SumAndCount) aoc.Answer {
 	sumRemainingInGrid := 0
-	for i := (lineIndex/5)*5; i < ((lineIndex/5)*5) + 5; i++ {
+	for i := (lineIndex / 5) * 5; i < ((lineIndex/5)*5)+5; i++ {
 		sumRemainingInGrid += lineSums[i].sum
 	}
-	return sumRemainingInGrid*winningDrawNumber
+	return aoc.Product(sumRemainingInGrid, winningDrawNumber)
 }
 
 // Part 2: we play until the last winning grid. Then we need to return a similar output
 // as in part 1: the multiplication of the winning drawn number by the sum of the remaining
 // values in the winning grid
 func processPart2(
+	ctx context.Context,
 	drawnNumbers []int,
 	rowSums []SumAndCount,
 	rowReverseIndex map[int][]int,
 	colSums []SumAndCount,
 	colReverseIndex map[int][]int,
 	remainingGrids int,
-	startIndexDrawnNumber int) (int, error) {
+	startIndexDrawnNumber int) (aoc.Answer, error) {
 	// We keep playing
-	for i := startIndexDrawnNumber+1; i < len(drawnNumbers); i++ {
+	for i := startIndexDrawnNumber + 1; i < len(drawnNumbers); i++ {
+		if err := aoc.Canceled(ctx, i); err != nil {
+			return aoc.Answer{}, err
+		}
 		draw := drawnNumbers[i]
 		// For each drawn number, we look in the rowReverseIndex map in which row we'll find them
-		for _ , gridLine := range rowReverseIndex[draw] {
+		for _, gridLine := range rowReverseIndex[draw] {
 			sumAndCount := rowSums[gridLine]
 			sumAndCount.sum -= draw
 			sumAndCount.count--
@@ -244,7 +278,7 @@ func processPart2(
 		}
 
 		// For each drawn number, we look in the colReverseIndex map in which column we'll find them
-		for _ , gridCol := range colReverseIndex[draw] {
+		for _, gridCol := range colReverseIndex[draw] {
 			sumAndCount := colSums[gridCol]
 			sumAndCount.sum -= draw
 			sumAndCount.count--
@@ -264,7 +298,7 @@ func processPart2(
 	}
 
 	// all numbers have been drawn and we have multiple remaining grids
-	return 0, errors.New("multiple remaining grids")
+	return aoc.Answer{}, ErrMultipleRemainingGrids
 }
 
 // Returns whether the grid for the given lineIndex has already won
@@ -315,4 +349,4 @@ func countRemainingNonWinningGridsByX(xSums []SumAndCount) int {
 	}
 
 	return countRemainingGridsByX
-}
\ No newline at end of file
+}
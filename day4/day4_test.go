@@ -0,0 +1,65 @@
+package day4
+
+import (
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc/aoctest"
+)
+
+// The examples given in the puzzle, with their answers
+var examples = []aoctest.Example{
+	{File: "example.txt", Part1: 4512, Part2: 1924},
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.CheckExamples(t, 4, examples)
+}
+
+func TestSolutionsAgree(t *testing.T) {
+	aoctest.CheckSolutions(t, 4, Generate(1000))
+}
+
+func TestCanceled(t *testing.T) {
+	aoctest.CheckCanceled(t, 4, Generate(1000))
+}
+
+func TestVisualize(t *testing.T) {
+	aoctest.CheckVisualize(t, 4, Generate(100))
+}
+
+// Number of grids in the synthetic input used by the benchmarks
+// It is well above the size of the real input
+const benchSize = 1000
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, 4, Generate(benchSize))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, 4, 1, Generate(benchSize))
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, 4, 2, Generate(benchSize))
+}
+
+func FuzzParse(f *testing.F) {
+	for _, example := range examples {
+		data, err := files.ReadFile(example.File)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(Generate(10))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		g, err := Parse(data)
+		if err != nil {
+			return
+		}
+		// The parts count on every grid having 5 rows and 5 columns
+		if len(g.rowSums)%5 != 0 || len(g.rowSums) != len(g.colSums) {
+			t.Fatalf("got %d rows and %d columns", len(g.rowSums), len(g.colSums))
+		}
+	})
+}
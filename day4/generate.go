@@ -0,0 +1,39 @@
+package day4
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Generate returns a synthetic input of n bingo grids
+// Like in the real inputs, the numbers go from 0 to 99, every grid holds
+// different numbers and all the numbers are drawn, in a random order, so
+// every grid ends up winning
+func Generate(n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	data := make([]byte, 0, 300+n*76)
+	for i, number := range r.Perm(100) {
+		if i > 0 {
+			data = append(data, ',')
+		}
+		data = strconv.AppendInt(data, int64(number), 10)
+	}
+	for grid := 0; grid < n; grid++ {
+		data = append(data, '\n')
+		numbers := r.Perm(100)
+		for row := 0; row < 5; row++ {
+			data = append(data, '\n')
+			for col := 0; col < 5; col++ {
+				if col > 0 {
+					data = append(data, ' ')
+				}
+				number := numbers[row*5+col]
+				if number < 10 {
+					data = append(data, ' ')
+				}
+				data = strconv.AppendInt(data, int64(number), 10)
+			}
+		}
+	}
+	return data
+}
@@ -0,0 +1,93 @@
+package day4
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// Grids shown in a frame, the others are only counted
+const (
+	visibleGrids = 10
+	gridsPerRow  = 5
+)
+
+// Visualize draws the numbers one by one, marking them on the grids, until
+// every grid has won. The grids that won are shown in green
+func Visualize(ctx context.Context, g Game, show func(frame string) error) error {
+	grids := g.grids()
+	marked := make(map[int]bool)
+	// Marked numbers in each row and column of all the grids, like rowSums and colSums
+	rowMarks := make([]int, len(g.rowSums))
+	colMarks := make([]int, len(g.colSums))
+	won := make([]bool, len(grids))
+	winners := 0
+
+	for index, draw := range g.drawnNumbers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		marked[draw] = true
+		for k, row := range g.rowReverseIndex[draw] {
+			col := g.colReverseIndex[draw][k]
+			rowMarks[row]++
+			colMarks[col]++
+			if (rowMarks[row] == 5 || colMarks[col] == 5) && !won[row/5] {
+				won[row/5] = true
+				winners++
+			}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s - draw %d of %d: %s\n\n", visual.Bold("Day 4"), index+1, len(g.drawnNumbers), visual.Highlight(fmt.Sprint(draw)))
+		for first := 0; first < len(grids) && first < visibleGrids; first += gridsPerRow {
+			for row := 0; row < 5; row++ {
+				for grid := first; grid < first+gridsPerRow && grid < len(grids) && grid < visibleGrids; grid++ {
+					for _, number := range grids[grid][row] {
+						cell := fmt.Sprintf("%3d", number)
+						switch {
+						case number == draw:
+							cell = visual.Highlight(cell)
+						case marked[number] && won[grid]:
+							cell = visual.Win(cell)
+						case marked[number]:
+							cell = visual.Bold(cell)
+						case won[grid]:
+							cell = visual.Faint(cell)
+						}
+						b.WriteString(cell)
+					}
+					b.WriteString("    ")
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		if len(grids) > visibleGrids {
+			fmt.Fprintf(&b, "... and %d more grids\n\n", len(grids)-visibleGrids)
+		}
+		fmt.Fprintf(&b, "Grids that won: %s of %d", visual.Win(fmt.Sprint(winners)), len(grids))
+		if err := show(b.String()); err != nil {
+			return err
+		}
+		if winners == len(grids) {
+			break
+		}
+	}
+	return nil
+}
+
+// Rebuilds the grids from the reverse indexes: a number at the same position
+// in both indexes is in the same grid, at the crossing of that row and column
+func (g Game) grids() [][5][5]int {
+	grids := make([][5][5]int, len(g.rowSums)/5)
+	for number, rows := range g.rowReverseIndex {
+		for k, row := range rows {
+			col := g.colReverseIndex[number][k]
+			grids[row/5][row%5][col%5] = number
+		}
+	}
+	return grids
+}
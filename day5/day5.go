@@ -0,0 +1,433 @@
+package day5
+
+import (
+	"context"
+	"embed"
+	"math"
+	"regexp"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/input"
+)
+
+// A line of vents in the input file
+var lineRe = regexp.MustCompile(`^(\d+),(\d+) -> (\d+),(\d+)$`)
+
+// A point of the ocean floor, used as the key of the maps counting the lines over each point
+type point struct {
+	x int
+	y int
+}
+
+//go:embed *.txt
+var files embed.FS
+
+func init() {
+	aoc.Register(aoc.Day{
+		Number: 5,
+		Parse:  func(data []byte) (interface{}, error) { return Parse(data) },
+		Part1: []aoc.Solution{
+			{Name: "map", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(processPart1(ctx, p.([][]int)))
+			}},
+			{Name: "grid", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(countOnGrid(ctx, p.([][]int), false))
+			}},
+			{Name: "analytic", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(countIntersections(ctx, p.([][]int), false))
+			}},
+		},
+		Part2: []aoc.Solution{
+			{Name: "map", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(processPart2(ctx, p.([][]int)))
+			}},
+			{Name: "grid", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(countOnGrid(ctx, p.([][]int), true))
+			}},
+			{Name: "analytic", Solve: func(ctx context.Context, p interface{}) (aoc.Answer, error) {
+				return aoc.IntAnswer(countIntersections(ctx, p.([][]int), true))
+			}},
+		},
+		Generate: Generate,
+		Visualize: func(ctx context.Context, p interface{}, show func(string) error) error {
+			return Visualize(ctx, p.([][]int), show)
+		},
+		Files: files,
+	})
+}
+
+// Read the input file. It contains numbers representing 2 set of x,y coordinates
+// These lines are written as `x1,y1 -> x2,y2`
+// Returns an array in which each row contains the set of 4 coordinates
+func Parse(file []byte) ([][]int, error) {
+	rawCoords, err := input.IntRecords(file, lineRe)
+	if err != nil {
+		return nil, err
+	}
+
+	// At this point, each coord[] contains x1,y1,x2,y2
+	for index, coord := range rawCoords {
+		// Lines can only be horizontal, vertical or diagonal at 45 degrees
+		if coord[0] != coord[2] && coord[1] != coord[3] && abs(coord[2]-coord[0]) != abs(coord[3]-coord[1]) {
+			return nil, &aoc.ParseError{Line: index + 1, Col: 1, Msg: "line is neither horizontal, vertical nor diagonal at 45 degrees"}
+		}
+	}
+
+	return rawCoords, nil
+}
+
+// Part 1: Based on the coordinates, trace each vertical and horizontal lines
+// and count the number of points where more than one line pass over it
+func processPart1(ctx context.Context, rawCoordinates [][]int) (int, error) {
+	countPtsOver1 := 0
+	// The following map will contain points where
+	// * the key is the coordinates of the point
+	// * the value is the number of lines that pass by that point
+	ptMap := make(map[point]int)
+
+	// Points traced so far, lines can be long so the context is checked along them
+	steps := 0
+	for _, coord := range rawCoordinates {
+		// We care only about vertical and horizontal lines
+		if coord[0] == coord[2] {
+			// Vertical lines
+			start := 0
+			end := 0
+			if coord[1] <= coord[3] {
+				start = coord[1]
+				end = coord[3]
+			} else {
+				start = coord[3]
+				end = coord[1]
+			}
+			for i := start; i <= end; i++ {
+				if err := aoc.Canceled(ctx, steps); err != nil {
+					return 0, err
+				}
+				steps++
+				ptMap[point{coord[0], i}] += 1
+				if ptMap[point{coord[0], i}] == 2 {
+					countPtsOver1++
+				}
+			}
+		} else if coord[1] == coord[3] {
+			// Horizontal lines
+			start := 0
+			end := 0
+			if coord[0] <= coord[2] {
+				start = coord[0]
+				end = coord[2]
+			} else {
+				start = coord[2]
+				end = coord[0]
+			}
+			for i := start; i <= end; i++ {
+				if err := aoc.Canceled(ctx, steps); err != nil {
+					return 0, err
+				}
+				steps++
+				ptMap[point{i, coord[1]}] += 1
+				if ptMap[point{i, coord[1]}] == 2 {
+					countPtsOver1++
+				}
+			}
+		}
+	}
+
+	return countPtsOver1, nil
+}
+
+// Part 2: Based on the coordinates, trace each vertical, horizontal and diagonal lines
+// and count the number of points where more than one line pass over it
+func processPart2(ctx context.Context, rawCoordinates [][]int) (int, error) {
+	countPtsOver1 := 0
+	// The following map will contain points where
+	// * the key is the coordinates of the point
+	// * the value is the number of lines that pass by that point
+	ptMap := make(map[point]int)
+
+	// Points traced so far, lines can be long so the context is checked along them
+	steps := 0
+	for _, coord := range rawCoordinates {
+		// Let's do vertical, horizontal and finally diagonal lines
+		if coord[0] == coord[2] {
+			// Vertical lines
+			start := 0
+			end := 0
+			if coord[1] <= coord[3] {
+				start = coord[1]
+				end = coord[3]
+			} else {
+				start = coord[3]
+				end = coord[1]
+			}
+			for i := start; i <= end; i++ {
+				if err := aoc.Canceled(ctx, steps); err != nil {
+					return 0, err
+				}
+				steps++
+				ptMap[point{coord[0], i}] += 1
+				if ptMap[point{coord[0], i}] == 2 {
+					countPtsOver1++
+				}
+			}
+		} else if coord[1] == coord[3] {
+			// Horizontal lines
+			start := 0
+			end := 0
+			if coord[0] <= coord[2] {
+				start = coord[0]
+				end = coord[2]
+			} else {
+				start = coord[2]
+				end = coord[0]
+			}
+			for i := start; i <= end; i++ {
+				if err := aoc.Canceled(ctx, steps); err != nil {
+					return 0, err
+				}
+				steps++
+				ptMap[point{i, coord[1]}] += 1
+				if ptMap[point{i, coord[1]}] == 2 {
+					countPtsOver1++
+				}
+			}
+		} else {
+			// Time for diagonal lines
+			xFactor, yFactor := 0, 0
+			if coord[0] <= coord[2] {
+				xFactor = 1
+			} else {
+				xFactor = -1
+			}
+			if coord[1] <= coord[3] {
+				yFactor = 1
+			} else {
+				yFactor = -1
+			}
+
+			i := coord[0]
+			j := coord[1]
+			for cpt := 0; cpt <= int(math.Abs(float64(coord[2]-coord[0]))); cpt++ {
+				if err := aoc.Canceled(ctx, steps); err != nil {
+					return 0, err
+				}
+				steps++
+				ptMap[point{i, j}] += 1
+				if ptMap[point{i, j}] == 2 {
+					countPtsOver1++
+				}
+				i += xFactor
+				j += yFactor
+			}
+		}
+	}
+
+	return countPtsOver1, nil
+}
+
+// Largest number of points of the grid of countOnGrid, one byte each
+const maxGridArea = 1 << 28
+
+// Same as processPart1 (diagonals = false) and processPart2 (diagonals = true),
+// but the points are counted on a grid the size of the ocean floor instead of a map
+// It costs memory proportional to the area covered by the lines, but saves
+// hashing every single point. Lines spread over more than maxGridArea points
+// are counted with the map instead
+func countOnGrid(ctx context.Context, rawCoordinates [][]int, diagonals bool) (int, error) {
+	if len(rawCoordinates) == 0 {
+		return 0, nil
+	}
+	// Find the bounds of the area covered by the lines
+	minX, minY := rawCoordinates[0][0], rawCoordinates[0][1]
+	maxX, maxY := minX, minY
+	for _, coord := range rawCoordinates {
+		for k := 0; k < 4; k += 2 {
+			if coord[k] < minX {
+				minX = coord[k]
+			}
+			if coord[k] > maxX {
+				maxX = coord[k]
+			}
+			if coord[k+1] < minY {
+				minY = coord[k+1]
+			}
+			if coord[k+1] > maxY {
+				maxY = coord[k+1]
+			}
+		}
+	}
+	// The spans are computed without sign, the coordinates can be far enough
+	// apart for their difference to overflow an int
+	spanX, spanY := uint64(maxX-minX), uint64(maxY-minY)
+	if spanX >= maxGridArea || spanY >= maxGridArea || (spanX+1)*(spanY+1) > maxGridArea {
+		if diagonals {
+			return processPart2(ctx, rawCoordinates)
+		}
+		return processPart1(ctx, rawCoordinates)
+	}
+	width, height := int(spanX)+1, int(spanY)+1
+	grid := make([]uint8, width*height)
+
+	countPtsOver1 := 0
+	steps := 0
+	for _, coord := range rawCoordinates {
+		dx, dy := sign(coord[2]-coord[0]), sign(coord[3]-coord[1])
+		if dx != 0 && dy != 0 && !diagonals {
+			continue
+		}
+		// Number of steps along the line, whatever its direction
+		length := (coord[2] - coord[0]) * dx
+		if dx == 0 {
+			length = (coord[3] - coord[1]) * dy
+		}
+		x, y := coord[0]-minX, coord[1]-minY
+		for cpt := 0; cpt <= length; cpt++ {
+			if err := aoc.Canceled(ctx, steps); err != nil {
+				return 0, err
+			}
+			steps++
+			// No need to count further than 2
+			if grid[y*width+x] < 2 {
+				grid[y*width+x]++
+				if grid[y*width+x] == 2 {
+					countPtsOver1++
+				}
+			}
+			x += dx
+			y += dy
+		}
+	}
+
+	return countPtsOver1, nil
+}
+
+// A line of vents as a starting point, a direction and a number of steps
+// The direction moves by -1, 0 or 1 on each axis
+type segment struct {
+	start  point
+	dir    point
+	length int
+}
+
+func newSegment(coord []int) segment {
+	dx, dy := sign(coord[2]-coord[0]), sign(coord[3]-coord[1])
+	length := abs(coord[2] - coord[0])
+	if dx == 0 {
+		length = abs(coord[3] - coord[1])
+	}
+	if dx == 0 && dy == 0 {
+		// A single point is a vertical line, as in processPart1, it needs a direction
+		// or it would look parallel to every other line
+		dy = 1
+	}
+	return segment{point{coord[0], coord[1]}, point{dx, dy}, length}
+}
+
+// Point of the segment after the given number of steps from its start
+func (s segment) at(step int) point {
+	return point{s.start.x + step*s.dir.x, s.start.y + step*s.dir.y}
+}
+
+// Same as processPart1 (diagonals = false) and processPart2 (diagonals = true),
+// without tracing the lines: the points where lines overlap are found by
+// computing the intersection of every pair of lines
+// It only depends on the number of lines, not on their lengths, except when
+// lines overlap along a stretch
+func countIntersections(ctx context.Context, rawCoordinates [][]int, diagonals bool) (int, error) {
+	segments := make([]segment, 0, len(rawCoordinates))
+	for index, coord := range rawCoordinates {
+		if err := aoc.Canceled(ctx, index); err != nil {
+			return 0, err
+		}
+		s := newSegment(coord)
+		if s.dir.x != 0 && s.dir.y != 0 && !diagonals {
+			continue
+		}
+		segments = append(segments, s)
+	}
+
+	// A point can be at the intersection of several pairs of lines, count it once
+	overlaps := make(map[point]bool)
+	pairs := 0
+	for i, s1 := range segments {
+		for _, s2 := range segments[i+1:] {
+			if err := aoc.Canceled(ctx, pairs); err != nil {
+				return 0, err
+			}
+			pairs++
+			if err := intersect(ctx, s1, s2, overlaps); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return len(overlaps), nil
+}
+
+// Adds the points both segments go through to the set of points
+// Only lines overlapping along a stretch can take long, the context is checked
+// along the stretch
+func intersect(ctx context.Context, s1 segment, s2 segment, points map[point]bool) error {
+	// Solve s1.start + t*s1.dir = s2.start + u*s2.dir
+	// The determinant is 0 when the lines are parallel
+	diff := point{s2.start.x - s1.start.x, s2.start.y - s1.start.y}
+	det := s2.dir.x*s1.dir.y - s1.dir.x*s2.dir.y
+	if det != 0 {
+		tNum := s2.dir.x*diff.y - diff.x*s2.dir.y
+		uNum := s1.dir.x*diff.y - diff.x*s1.dir.y
+		// Diagonals can cross between two points of the grid, they don't share any point then
+		if tNum%det != 0 || uNum%det != 0 {
+			return nil
+		}
+		t, u := tNum/det, uNum/det
+		if t >= 0 && t <= s1.length && u >= 0 && u <= s2.length {
+			points[s1.at(t)] = true
+		}
+		return nil
+	}
+
+	// Parallel lines only share points when they are on the same line
+	if diff.x*s1.dir.y-diff.y*s1.dir.x != 0 {
+		return nil
+	}
+	// Go through s2 in the same direction as s1
+	if s2.dir != s1.dir {
+		s2 = segment{s2.at(s2.length), s1.dir, s2.length}
+		diff = point{s2.start.x - s1.start.x, s2.start.y - s1.start.y}
+	}
+	// Number of steps from the start of s1 to the start of s2
+	offset := diff.x * s1.dir.x
+	if s1.dir.x == 0 {
+		offset = diff.y * s1.dir.y
+	}
+	first, last := offset, offset+s2.length
+	if first < 0 {
+		first = 0
+	}
+	if last > s1.length {
+		last = s1.length
+	}
+	for step := first; step <= last; step++ {
+		if err := aoc.Canceled(ctx, step-first); err != nil {
+			return err
+		}
+		points[s1.at(step)] = true
+	}
+	return nil
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+func sign(value int) int {
+	if value < 0 {
+		return -1
+	} else if value > 0 {
+		return 1
+	}
+	return 0
+}
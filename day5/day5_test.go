@@ -0,0 +1,127 @@
+package day5
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	"github.com/aymec/adventofcode2021/aoc/aoctest"
+)
+
+// The examples given in the puzzle, with their answers
+var examples = []aoctest.Example{
+	{File: "example.txt", Part1: 5, Part2: 12},
+}
+
+func TestExamples(t *testing.T) {
+	aoctest.CheckExamples(t, 5, examples)
+}
+
+func TestSolutionsAgree(t *testing.T) {
+	aoctest.CheckSolutions(t, 5, Generate(1000))
+}
+
+// A long line of vents
+const longLine = "0,0 -> 0,999999999\n"
+
+func TestCanceled(t *testing.T) {
+	aoctest.CheckCanceled(t, 5, Generate(1000))
+	aoctest.CheckCanceled(t, 5, []byte(longLine))
+}
+
+// The solutions stop in the middle of a line when the deadline passes. The line
+// is there twice, for the pair of lines of the analytic solutions
+func TestDeadlineLongLine(t *testing.T) {
+	day, _ := aoc.Lookup(5)
+	puzzle, err := day.Parse([]byte(longLine + longLine))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, solutions := range day.Parts() {
+		for _, solution := range solutions {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			_, err := solution.Solve(ctx, puzzle)
+			cancel()
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("part %d, %s: got %v, expected %v", index+1, solution.Name, err, context.DeadlineExceeded)
+			}
+		}
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	aoctest.CheckVisualize(t, 5, Generate(100))
+}
+
+// Lines far apart on a floor too large for a grid
+func TestLargeFloor(t *testing.T) {
+	rawCoordinates, err := Parse([]byte("0,0 -> 0,999\n0,5 -> 0,5\n999999999,999999999 -> 999999990,999999990\n999999995,999999995 -> 999999995,999999999\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, diagonals := range []bool{false, true} {
+		got, err := countOnGrid(context.Background(), rawCoordinates, diagonals)
+		expected := 1
+		if diagonals {
+			expected = 2
+		}
+		if err != nil || got != expected {
+			t.Errorf("diagonals %t: got %d, %v, expected %d", diagonals, got, err, expected)
+		}
+	}
+}
+
+// Coordinates at both ends of the ints, the size of the floor overflows an int
+func TestFloorOverflow(t *testing.T) {
+	rawCoordinates := [][]int{
+		{math.MinInt, 0, math.MinInt, 1},
+		{math.MaxInt, 0, math.MaxInt, 1},
+		{math.MaxInt, 1, math.MaxInt, 1},
+	}
+	for _, diagonals := range []bool{false, true} {
+		if got, err := countOnGrid(context.Background(), rawCoordinates, diagonals); err != nil || got != 1 {
+			t.Errorf("diagonals %t: got %d, %v, expected 1", diagonals, got, err)
+		}
+	}
+}
+
+// Number of lines of vents in the synthetic input used by the benchmarks
+// It is well above the size of the real input
+const benchSize = 10000
+
+func BenchmarkParse(b *testing.B) {
+	aoctest.BenchmarkParse(b, 5, Generate(benchSize))
+}
+
+func BenchmarkPart1(b *testing.B) {
+	aoctest.BenchmarkPart(b, 5, 1, Generate(benchSize))
+}
+
+func BenchmarkPart2(b *testing.B) {
+	aoctest.BenchmarkPart(b, 5, 2, Generate(benchSize))
+}
+
+func FuzzParse(f *testing.F) {
+	for _, example := range examples {
+		data, err := files.ReadFile(example.File)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add(Generate(10))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rawCoordinates, err := Parse(data)
+		if err != nil {
+			return
+		}
+		for _, coord := range rawCoordinates {
+			if len(coord) != 4 {
+				t.Fatalf("got %d coordinates in a line", len(coord))
+			}
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package day5
+
+import (
+	"math/rand"
+	"strconv"
+)
+
+// Generate returns a synthetic input of n lines of vents
+// Like in the real inputs, the coordinates go from 0 to 999 and the lines are
+// horizontal, vertical or diagonal at 45 degrees
+func Generate(n int) []byte {
+	r := rand.New(rand.NewSource(int64(n)))
+	data := make([]byte, 0, n*20)
+	for i := 0; i < n; i++ {
+		x1, y1 := r.Intn(1000), r.Intn(1000)
+		x2, y2 := x1, y1
+		switch r.Intn(3) {
+		case 0:
+			x2 = r.Intn(1000)
+		case 1:
+			y2 = r.Intn(1000)
+		default:
+			// Diagonal, stay inside the 1000x1000 area
+			length := r.Intn(1000)
+			dx, dy := 1, 1
+			if x1+length > 999 {
+				dx = -1
+			}
+			if y1+length > 999 {
+				dy = -1
+			}
+			if x1-length < 0 && dx < 0 || y1-length < 0 && dy < 0 {
+				length = 0
+			}
+			x2, y2 = x1+dx*length, y1+dy*length
+		}
+		if i > 0 {
+			data = append(data, '\n')
+		}
+		data = strconv.AppendInt(data, int64(x1), 10)
+		data = append(data, ',')
+		data = strconv.AppendInt(data, int64(y1), 10)
+		data = append(data, " -> "...)
+		data = strconv.AppendInt(data, int64(x2), 10)
+		data = append(data, ',')
+		data = strconv.AppendInt(data, int64(y2), 10)
+	}
+	return data
+}
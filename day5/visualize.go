@@ -0,0 +1,104 @@
+package day5
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/visual"
+)
+
+// Largest size of the map of the ocean floor in the terminal
+// Larger floors are scaled down, a character then covers several points
+const (
+	mapWidth  = 70
+	mapHeight = 30
+)
+
+// Visualize traces the lines one by one on a map of the ocean floor, diagonals
+// included as in Part2. The points covered by two lines or more are in green
+func Visualize(ctx context.Context, rawCoordinates [][]int, show func(frame string) error) error {
+	if len(rawCoordinates) == 0 {
+		return nil
+	}
+	minX, minY := rawCoordinates[0][0], rawCoordinates[0][1]
+	maxX, maxY := minX, minY
+	for _, coord := range rawCoordinates {
+		for k := 0; k < 4; k += 2 {
+			minX, maxX = bounds(coord[k], minX, maxX)
+			minY, maxY = bounds(coord[k+1], minY, maxY)
+		}
+	}
+	width, height := maxX-minX+1, maxY-minY+1
+	if width > mapWidth {
+		width = mapWidth
+	}
+	if height > mapHeight {
+		height = mapHeight
+	}
+
+	// Lines over each point of the floor, and over each character of the map
+	points := make(map[point]int)
+	cells := make([][]int, height)
+	for y := range cells {
+		cells[y] = make([]int, width)
+	}
+	overlaps := 0
+	for index, coord := range rawCoordinates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s := newSegment(coord)
+		current := make(map[point]bool)
+		for step := 0; step <= s.length; step++ {
+			p := s.at(step)
+			points[p]++
+			if points[p] == 2 {
+				overlaps++
+			}
+			cell := point{visual.Scale(p.x, minX, maxX, width), visual.Scale(p.y, minY, maxY, height)}
+			if points[p] > cells[cell.y][cell.x] {
+				cells[cell.y][cell.x] = points[p]
+			}
+			current[cell] = true
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s - line %d of %d: %d,%d -> %d,%d\n\n", visual.Bold("Day 5"), index+1, len(rawCoordinates), coord[0], coord[1], coord[2], coord[3])
+		for y, row := range cells {
+			for x, count := range row {
+				c := "."
+				if count > 0 {
+					c = fmt.Sprint(count)
+					if count > 9 {
+						c = "+"
+					}
+				}
+				switch {
+				case current[point{x, y}]:
+					c = visual.Highlight(c)
+				case count >= 2:
+					c = visual.Win(c)
+				}
+				b.WriteString(c)
+			}
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\nPoints covered by two lines or more: %s", visual.Win(fmt.Sprint(overlaps)))
+		if err := show(b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the bounds extended to include value
+func bounds(value int, min int, max int) (int, int) {
+	if value < min {
+		min = value
+	}
+	if value > max {
+		max = value
+	}
+	return min, max
+}
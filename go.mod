@@ -1,3 +1,3 @@
 module github.com/aymec/adventofcode2021
 
-go 1.17
+go 1.18
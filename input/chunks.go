@@ -0,0 +1,146 @@
+package input
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// MinChunkSize is the smallest byte range Chunks splits an input in, smaller
+// inputs are split in fewer ranges than workers
+const MinChunkSize = 1 << 16
+
+// Chunks returns the number of byte ranges to split an input of size bytes in
+// for workers goroutines, at least 1
+func Chunks(size int64, workers int) int {
+	chunks := int64(workers)
+	if max := size/MinChunkSize + 1; chunks > max {
+		chunks = max
+	}
+	if chunks < 1 {
+		chunks = 1
+	}
+	return int(chunks)
+}
+
+// ChunkedLines reads the lines of an input of size bytes without loading it as
+// a whole: it is split in chunks byte ranges, and the lines of the ranges are
+// read by workers goroutines at the same time. A range holds the lines that
+// start in it, whatever the range they end in
+// line is called with every line, without its `\n` and `\r` as Lines returns
+// them, the index of its range and its number in the range from 1. The lines
+// of a range come in order, the line is only valid during the call
+// Reading a range stops at the first error of line, and ChunkedLines returns
+// the error of the first range that failed. The line of a *aoc.ParseError is
+// moved from the line in the range to the line in the input
+func ChunkedLines(ctx context.Context, r io.ReaderAt, size int64, chunks int, workers int, line func(chunk int, number int, text []byte) error) error {
+	type result struct {
+		lines int
+		err   error
+	}
+	results := make([]result, chunks)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range jobs {
+				start, end := size*int64(chunk)/int64(chunks), size*int64(chunk+1)/int64(chunks)
+				results[chunk].lines, results[chunk].err = readChunk(ctx, r, size, start, end, func(number int, text []byte) error {
+					return line(chunk, number, text)
+				})
+			}
+		}()
+	}
+	for chunk := 0; chunk < chunks; chunk++ {
+		jobs <- chunk
+	}
+	close(jobs)
+	wg.Wait()
+
+	lines := 0
+	for _, result := range results {
+		if result.err != nil {
+			var parseErr *aoc.ParseError
+			if errors.As(result.err, &parseErr) {
+				moved := *parseErr
+				moved.Line += lines
+				return &moved
+			}
+			return result.err
+		}
+		lines += result.lines
+	}
+	return nil
+}
+
+// Reads the lines starting from start to end, and returns how many there are
+func readChunk(ctx context.Context, r io.ReaderAt, size int64, start int64, end int64, line func(number int, text []byte) error) (int, error) {
+	pos := start
+	var reader *bufio.Reader
+	if pos == 0 {
+		reader = bufio.NewReaderSize(io.NewSectionReader(r, 0, size), MinChunkSize)
+	} else {
+		// The range starts on a line when the byte before it ends a line, otherwise
+		// that line belongs to the previous range and is skipped
+		reader = bufio.NewReaderSize(io.NewSectionReader(r, pos-1, size-pos+1), MinChunkSize)
+		b, err := reader.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != '\n' {
+			skipped, err := readLine(reader)
+			pos += int64(len(skipped))
+			if err == io.EOF {
+				return 0, nil
+			}
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	number := 0
+	for ; pos < end && pos < size; number++ {
+		if err := aoc.Canceled(ctx, number); err != nil {
+			return 0, err
+		}
+		text, err := readLine(reader)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		pos += int64(len(text))
+		if err := line(number+1, trimLine(text)); err != nil {
+			return 0, err
+		}
+	}
+	return number, nil
+}
+
+// Reads a line with its `\n`, however long it is
+func readLine(reader *bufio.Reader) ([]byte, error) {
+	line, err := reader.ReadSlice('\n')
+	if err != bufio.ErrBufferFull {
+		return line, err
+	}
+	// Longer than the buffer, it must be read whole
+	long := append([]byte(nil), line...)
+	rest, err := reader.ReadBytes('\n')
+	return append(long, rest...), err
+}
+
+// Drops the `\n` and `\r` at the end of a line, as Lines does
+func trimLine(line []byte) []byte {
+	if len(line) > 0 && line[len(line)-1] == '\n' {
+		line = line[:len(line)-1]
+	}
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+	return line
+}
@@ -0,0 +1,81 @@
+package input
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Reads the lines of data with ChunkedLines, in the order of the input
+func chunkedLines(data string, chunks int, line func(number int, text string) error) ([]string, error) {
+	lines := make([][]string, chunks)
+	err := ChunkedLines(context.Background(), strings.NewReader(data), int64(len(data)), chunks, 3, func(chunk int, number int, text []byte) error {
+		if number != len(lines[chunk])+1 {
+			return fmt.Errorf("got line %d after %d lines", number, len(lines[chunk]))
+		}
+		lines[chunk] = append(lines[chunk], string(text))
+		return line(number, string(text))
+	})
+	all := make([]string, 0)
+	for _, chunk := range lines {
+		all = append(all, chunk...)
+	}
+	return all, err
+}
+
+func TestChunkedLines(t *testing.T) {
+	// Long lines as well, longer than the buffer of the reader
+	long := strings.Repeat("x", 3*MinChunkSize)
+	for _, data := range []string{"a\nb\nc", "a\r\nbb\r\nccc\r\n", "\n\n\n", "a\n" + long + "\nb\n" + long} {
+		for _, chunks := range []int{1, 2, 3, 7, 64} {
+			got, err := chunkedLines(data, chunks, func(int, string) error { return nil })
+			if expected := Lines([]byte(data)); err != nil || !reflect.DeepEqual(got, expected) {
+				t.Errorf("%.20q in %d chunks: got %d lines, %v, expected %d lines", data, chunks, len(got), err, len(expected))
+			}
+		}
+	}
+}
+
+func TestChunkedLinesErrors(t *testing.T) {
+	data := strings.Repeat("ok\n", 100) + "bad\n" + strings.Repeat("ok\n", 100)
+	for _, chunks := range []int{1, 2, 5, len(data)} {
+		_, err := chunkedLines(data, chunks, func(number int, text string) error {
+			if text == "bad" {
+				return &aoc.ParseError{Line: number, Col: 2, Msg: "bad line"}
+			}
+			return nil
+		})
+		checkParseError(t, err, 101, 2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ChunkedLines(ctx, bytes.NewReader([]byte(data)), int64(len(data)), 4, 4, func(int, int, []byte) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, expected %v", err, context.Canceled)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	for _, test := range []struct {
+		size     int64
+		workers  int
+		expected int
+	}{
+		{0, 8, 1},
+		{MinChunkSize - 1, 8, 1},
+		{4 * MinChunkSize, 8, 5},
+		{100 * MinChunkSize, 8, 8},
+		{100, 0, 1},
+	} {
+		if got := Chunks(test.size, test.workers); got != test.expected {
+			t.Errorf("Chunks(%d, %d) = %d, expected %d", test.size, test.workers, got, test.expected)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package input holds the helpers the days use to read their input files:
+// splitting lines and blocks, reading integers, grids of digits and records
+// described by a regular expression, and reading the lines of huge inputs in
+// parallel without loading them
+//
+// Errors are *aoc.ParseError, with the line and column of the problem
+// Lines and columns start at 1
+package input
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Lines splits the input in lines
+// A newline at the end of the input does not make an extra empty line, and
+// the `\r` of Windows line endings are dropped
+func Lines(data []byte) []string {
+	text := string(data) // The only copy of the input, the lines share its memory
+	text = strings.TrimSuffix(text, "\n")
+	lines := strings.Split(text, "\n")
+	for index, line := range lines {
+		lines[index] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// Ints reads an input with one integer per line
+func Ints(data []byte) ([]int, error) {
+	lines := Lines(data)
+	values := make([]int, len(lines))
+	for index, line := range lines {
+		value, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, &aoc.ParseError{Line: index + 1, Col: 1, Msg: "invalid integer", Err: err}
+		}
+		values[index] = value
+	}
+	return values, nil
+}
+
+// CommaInts reads integers separated by commas, like `7,4,9,5,11`
+// lineNumber is the number of the line in the input, for the errors
+func CommaInts(line string, lineNumber int) ([]int, error) {
+	values := make([]int, 0, strings.Count(line, ",")+1)
+	col := 1
+	for {
+		end := strings.IndexByte(line, ',')
+		if end < 0 {
+			end = len(line)
+		}
+		value, err := strconv.Atoi(line[:end])
+		if err != nil {
+			return nil, &aoc.ParseError{Line: lineNumber, Col: col, Msg: "invalid integer", Err: err}
+		}
+		values = append(values, value)
+		if end == len(line) {
+			return values, nil
+		}
+		line = line[end+1:]
+		col += end + 1
+	}
+}
+
+// FieldInts reads integers separated by any number of spaces or tabs, like ` 8  2 23  4 24`
+// lineNumber is the number of the line in the input, for the errors
+func FieldInts(line string, lineNumber int) ([]int, error) {
+	values := make([]int, 0, countFields(line))
+	for start := 0; start < len(line); {
+		if isSpace(line[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(line) && !isSpace(line[end]) {
+			end++
+		}
+		value, err := strconv.Atoi(line[start:end])
+		if err != nil {
+			return nil, &aoc.ParseError{Line: lineNumber, Col: start + 1, Msg: "invalid integer", Err: err}
+		}
+		values = append(values, value)
+		start = end
+	}
+	return values, nil
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func countFields(line string) int {
+	count := 0
+	for index := 0; index < len(line); index++ {
+		if !isSpace(line[index]) && (index == 0 || isSpace(line[index-1])) {
+			count++
+		}
+	}
+	return count
+}
+
+// Block is a group of consecutive non-empty lines
+type Block struct {
+	// Number of the first line of the block in the input
+	Line  int
+	Lines []string
+}
+
+// Blocks splits the input in groups of lines separated by empty lines
+// Several empty lines in a row are the same as one
+func Blocks(data []byte) []Block {
+	blocks := make([]Block, 0)
+	lines := Lines(data)
+	start := 0
+	for index := 0; index <= len(lines); index++ {
+		if index == len(lines) || len(lines[index]) == 0 {
+			if index > start {
+				blocks = append(blocks, Block{start + 1, lines[start:index]})
+			}
+			start = index + 1
+		}
+	}
+	return blocks
+}
+
+// Digits reads a grid of digits: every line has the same number of digits, and every
+// character is a digit. The value of the digit at row i and column j is in grid[i][j]
+func Digits(data []byte) ([][]uint8, error) {
+	lines := Lines(data)
+	width := len(lines[0])
+	// All the rows share the same backing array, a single allocation for the whole grid
+	cells := make([]uint8, len(lines)*width)
+	grid := make([][]uint8, len(lines))
+	for row, line := range lines {
+		if len(line) != width {
+			return nil, &aoc.ParseError{Line: row + 1, Col: 1, Msg: fmt.Sprintf("expected %d digits, found %d", width, len(line))}
+		}
+		grid[row] = cells[row*width : (row+1)*width]
+		for col := 0; col < width; col++ {
+			if line[col] < '0' || line[col] > '9' {
+				return nil, &aoc.ParseError{Line: row + 1, Col: col + 1, Msg: fmt.Sprintf("unexpected character %q, expected a digit", line[col])}
+			}
+			grid[row][col] = line[col] - '0'
+		}
+	}
+	return grid, nil
+}
+
+// IntRecords reads an input where every line is a record matching the regular
+// expression re, and every group of the expression captures an integer
+// The expression should match the whole line, with ^ and $
+// The record at index i is read from line i+1
+func IntRecords(data []byte, re *regexp.Regexp) ([][]int, error) {
+	lines := Lines(data)
+	groups := re.NumSubexp()
+	// All the records share the same backing array, a single allocation for all of them
+	values := make([]int, 0, len(lines)*groups)
+	records := make([][]int, 0, len(lines))
+	for index, line := range lines {
+		bounds := re.FindStringSubmatchIndex(line)
+		if bounds == nil {
+			return nil, &aoc.ParseError{Line: index + 1, Col: 1, Msg: fmt.Sprintf("%q does not match %s", line, re)}
+		}
+		for group := 1; group <= groups; group++ {
+			start, end := bounds[2*group], bounds[2*group+1]
+			if start < 0 {
+				return nil, &aoc.ParseError{Line: index + 1, Col: 1, Msg: fmt.Sprintf("group %d of %s is missing", group, re)}
+			}
+			value, err := strconv.Atoi(line[start:end])
+			if err != nil {
+				return nil, &aoc.ParseError{Line: index + 1, Col: start + 1, Msg: "invalid integer", Err: err}
+			}
+			values = append(values, value)
+		}
+		records = append(records, values[len(values)-groups:len(values):len(values)])
+	}
+	return records, nil
+}
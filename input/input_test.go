@@ -0,0 +1,98 @@
+package input
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		data  string
+		lines []string
+	}{
+		{"a\nb", []string{"a", "b"}},
+		{"a\nb\n", []string{"a", "b"}},
+		{"a\r\nb\r\n", []string{"a", "b"}},
+		{"a\n\nb", []string{"a", "", "b"}},
+		{"", []string{""}},
+	}
+	for _, test := range tests {
+		if lines := Lines([]byte(test.data)); !reflect.DeepEqual(lines, test.lines) {
+			t.Errorf("Lines(%q) = %q, expected %q", test.data, lines, test.lines)
+		}
+	}
+}
+
+func TestBlocks(t *testing.T) {
+	blocks := Blocks([]byte("1,2\n\na\nb\n\n\nc\n"))
+	expected := []Block{{1, []string{"1,2"}}, {3, []string{"a", "b"}}, {7, []string{"c"}}}
+	if !reflect.DeepEqual(blocks, expected) {
+		t.Errorf("got %v, expected %v", blocks, expected)
+	}
+}
+
+// Checks that err is a *aoc.ParseError at the given position
+func checkParseError(t *testing.T, err error, line int, col int) {
+	t.Helper()
+	var parseErr *aoc.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Fatalf("got error %v, expected a parse error", err)
+	}
+	if parseErr.Line != line || parseErr.Col != col {
+		t.Errorf("got error at line %d column %d, expected line %d column %d", parseErr.Line, parseErr.Col, line, col)
+	}
+}
+
+func TestInts(t *testing.T) {
+	values, err := Ints([]byte("199\n-200\n208\n"))
+	if err != nil || !reflect.DeepEqual(values, []int{199, -200, 208}) {
+		t.Errorf("got %v, %v", values, err)
+	}
+	_, err = Ints([]byte("199\n20x\n208"))
+	checkParseError(t, err, 2, 1)
+}
+
+func TestCommaInts(t *testing.T) {
+	values, err := CommaInts("7,4,9,15", 1)
+	if err != nil || !reflect.DeepEqual(values, []int{7, 4, 9, 15}) {
+		t.Errorf("got %v, %v", values, err)
+	}
+	_, err = CommaInts("7,4,,15", 3)
+	checkParseError(t, err, 3, 5)
+}
+
+func TestFieldInts(t *testing.T) {
+	values, err := FieldInts(" 8  2 23\t4 24 ", 1)
+	if err != nil || !reflect.DeepEqual(values, []int{8, 2, 23, 4, 24}) {
+		t.Errorf("got %v, %v", values, err)
+	}
+	_, err = FieldInts(" 8  2 x3", 4)
+	checkParseError(t, err, 4, 7)
+}
+
+func TestDigits(t *testing.T) {
+	grid, err := Digits([]byte("012\n345\n"))
+	if err != nil || !reflect.DeepEqual(grid, [][]uint8{{0, 1, 2}, {3, 4, 5}}) {
+		t.Errorf("got %v, %v", grid, err)
+	}
+	_, err = Digits([]byte("012\n34"))
+	checkParseError(t, err, 2, 1)
+	_, err = Digits([]byte("012\n3a5"))
+	checkParseError(t, err, 2, 2)
+}
+
+func TestIntRecords(t *testing.T) {
+	re := regexp.MustCompile(`^(\d+),(\d+) -> (\d+),(\d+)$`)
+	records, err := IntRecords([]byte("0,9 -> 5,9\n8,0 -> 0,8\n"), re)
+	if err != nil || !reflect.DeepEqual(records, [][]int{{0, 9, 5, 9}, {8, 0, 0, 8}}) {
+		t.Errorf("got %v, %v", records, err)
+	}
+	_, err = IntRecords([]byte("0,9 -> 5,9\n8,0 - 0,8"), re)
+	checkParseError(t, err, 2, 1)
+	_, err = IntRecords([]byte("0,99999999999999999999 -> 5,9"), re)
+	checkParseError(t, err, 1, 3)
+}
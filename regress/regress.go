@@ -0,0 +1,147 @@
+// Package regress keeps the answers found for each input, to notice when a
+// change in a solver changes an answer
+//
+// The answers are kept in a text file, one answer per line, with the day, the
+// part, the SHA-256 of the input and the answer separated by spaces:
+//
+//	5 2 3f2a...9c01 18144
+//
+// The lines are sorted, so the file can be kept in git and compared easily
+package regress
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// Key identifies an answer: the part of a day, for a given input
+type Key struct {
+	Day  int
+	Part int
+	// InputHash is the SHA-256 of the input, see Hash
+	InputHash string
+}
+
+// Mismatch is an answer that changed since it was recorded
+type Mismatch struct {
+	Key
+	Recorded string
+	Found    string
+}
+
+// DB holds the recorded answers
+type DB struct {
+	answers map[Key]string
+	path    string
+}
+
+// Hash returns the hexadecimal SHA-256 of an input
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Load reads the answers recorded in the given file. A missing file gives an empty DB
+func Load(path string) (*DB, error) {
+	db := &DB{answers: make(map[Key]string), path: path}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return db, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("%s: %w", path, &aoc.ParseError{Line: line, Col: 1, Msg: "expected \"day part hash answer\""})
+		}
+		var key Key
+		if key.Day, err = strconv.Atoi(fields[0]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, &aoc.ParseError{Line: line, Col: 1, Msg: "invalid day", Err: err})
+		}
+		if key.Part, err = strconv.Atoi(fields[1]); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, &aoc.ParseError{Line: line, Col: len(fields[0]) + 2, Msg: "invalid part", Err: err})
+		}
+		key.InputHash = fields[2]
+		db.answers[key] = fields[3]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Check compares an answer with the recorded one
+// An answer that was never recorded is recorded, and changed is false
+// When the answer changed, the recorded one is kept unless update is true
+func (db *DB) Check(key Key, answer string, update bool) (mismatch Mismatch, changed bool) {
+	recorded, ok := db.answers[key]
+	if ok && recorded != answer {
+		if update {
+			db.answers[key] = answer
+		}
+		return Mismatch{key, recorded, answer}, true
+	}
+	db.answers[key] = answer
+	return Mismatch{}, false
+}
+
+// CheckError reports a part that failed as a mismatch when an answer was recorded for it
+func (db *DB) CheckError(key Key, err error) (mismatch Mismatch, changed bool) {
+	if recorded, ok := db.answers[key]; ok {
+		return Mismatch{key, recorded, "error: " + err.Error()}, true
+	}
+	return Mismatch{}, false
+}
+
+// Save writes the answers to the file they were loaded from
+func (db *DB) Save() error {
+	keys := make([]Key, 0, len(db.answers))
+	for key := range db.answers {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Day != keys[j].Day {
+			return keys[i].Day < keys[j].Day
+		}
+		if keys[i].Part != keys[j].Part {
+			return keys[i].Part < keys[j].Part
+		}
+		return keys[i].InputHash < keys[j].InputHash
+	})
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, "%d %d %s %s\n", key.Day, key.Part, key.InputHash, db.answers[key])
+	}
+	return os.WriteFile(db.path, []byte(b.String()), 0o644)
+}
+
+// String returns the mismatch as a diff between the recorded answer and the new one
+func (m Mismatch) String() string {
+	return fmt.Sprintf("day %d part %d, input %s:\n- %s\n+ %s", m.Day, m.Part, m.InputHash[:min(12, len(m.InputHash))], m.Recorded, m.Found)
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,47 @@
+package regress
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "answers.txt")
+	db, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := Key{Day: 5, Part: 2, InputHash: Hash([]byte("0,9 -> 5,9"))}
+
+	if _, changed := db.Check(key, "12", false); changed {
+		t.Error("a new answer is not a change")
+	}
+	if err := db.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Load what was saved
+	db, err = Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, changed := db.Check(key, "12", false); changed {
+		t.Error("the same answer is not a change")
+	}
+	mismatch, changed := db.Check(key, "13", false)
+	if !changed || mismatch.Recorded != "12" || mismatch.Found != "13" {
+		t.Errorf("got %v, %v", mismatch, changed)
+	}
+	// Without update, the recorded answer stays
+	if _, changed := db.Check(key, "13", true); !changed {
+		t.Error("expected a change")
+	}
+	// With update, the new answer is recorded
+	if _, changed := db.Check(key, "13", false); changed {
+		t.Error("expected the new answer to be recorded")
+	}
+	if _, changed := db.CheckError(key, errors.New("no winner")); !changed {
+		t.Error("an error instead of a recorded answer is a change")
+	}
+}
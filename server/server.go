@@ -0,0 +1,152 @@
+// Package server exposes the solvers of the registered days over HTTP, so other
+// tools can use them without running the aoc command
+//
+//	GET  /days                the numbers of the registered days
+//	POST /days/{n}/parts/{p}  solves part p of day n, the puzzle input is the body
+//
+// Every response is JSON. Errors are returned as {"error": "..."}
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aymec/adventofcode2021/aoc"
+)
+
+// DefaultMaxInputSize is the largest puzzle input accepted unless told otherwise
+// The real inputs are a few kilobytes, it leaves room for synthetic ones
+const DefaultMaxInputSize = 10 << 20
+
+// Server answers the HTTP requests, it is an http.Handler
+type Server struct {
+	// MaxInputSize is the largest body accepted, in bytes
+	MaxInputSize int64
+	// Timeout is the time given to a part to find its answer, 0 means no limit
+	// A part also stops when the client goes away
+	Timeout time.Duration
+}
+
+// New returns a server with the default limits and no timeout
+func New() *Server {
+	return &Server{MaxInputSize: DefaultMaxInputSize}
+}
+
+// Days is the response to GET /days
+type Days struct {
+	Days []int `json:"days"`
+}
+
+// Answer is the response to POST /days/{n}/parts/{p}
+// The answer is a JSON number, that can be larger than 64 bits
+// Error is set when the part could not be solved, Answer is then 0
+type Answer struct {
+	Day    int        `json:"day"`
+	Part   int        `json:"part"`
+	Answer aoc.Answer `json:"answer"`
+	Error  string     `json:"error,omitempty"`
+	// Time spent parsing the input and solving the part, in nanoseconds
+	ParseTime int64 `json:"parse_ns"`
+	SolveTime int64 `json:"solve_ns"`
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(path) == 1 && path[0] == "days":
+		if r.Method != http.MethodGet {
+			methodNotAllowed(w, http.MethodGet)
+			return
+		}
+		s.days(w)
+	case len(path) == 4 && path[0] == "days" && path[2] == "parts":
+		if r.Method != http.MethodPost {
+			methodNotAllowed(w, http.MethodPost)
+			return
+		}
+		s.solve(w, r, path[1], path[3])
+	default:
+		writeError(w, http.StatusNotFound, fmt.Errorf("no such endpoint %s", r.URL.Path))
+	}
+}
+
+func (s *Server) days(w http.ResponseWriter) {
+	days := Days{Days: make([]int, 0)}
+	for _, day := range aoc.Days() {
+		days.Days = append(days.Days, day.Number)
+	}
+	writeJSON(w, http.StatusOK, days)
+}
+
+func (s *Server) solve(w http.ResponseWriter, r *http.Request, dayArg string, partArg string) {
+	number, err := strconv.Atoi(dayArg)
+	day, ok := aoc.Lookup(number)
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("day %s is not solved", dayArg))
+		return
+	}
+	part, err := strconv.Atoi(partArg)
+	if err != nil || part < 1 || part > 2 {
+		writeError(w, http.StatusNotFound, fmt.Errorf("invalid part %s, expected 1 or 2", partArg))
+		return
+	}
+
+	// Read one byte more than the limit, to tell an input of the maximum size from a larger one
+	data, err := io.ReadAll(io.LimitReader(r.Body, s.MaxInputSize+1))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if int64(len(data)) > s.MaxInputSize {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("input larger than %d bytes", s.MaxInputSize))
+		return
+	}
+
+	result, err := aoc.RunPart(r.Context(), day, data, part, s.Timeout)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	solved := result.Parts[part-1]
+	answer := Answer{
+		Day:       number,
+		Part:      part,
+		Answer:    solved.Answer,
+		ParseTime: result.Parse.Elapsed.Nanoseconds(),
+		SolveTime: solved.Elapsed.Nanoseconds(),
+	}
+	status := http.StatusOK
+	if solved.Err != nil {
+		answer.Answer = aoc.Int(0)
+		answer.Error = solved.Err.Error()
+		status = http.StatusUnprocessableEntity
+		if errors.Is(solved.Err, context.DeadlineExceeded) {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, status, answer)
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed string) {
+	w.Header().Set("Allow", allowed)
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed, use %s", allowed))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aymec/adventofcode2021/aoc"
+	_ "github.com/aymec/adventofcode2021/day1"
+)
+
+const example = "199\n200\n208\n210\n200\n207\n240\n269\n260\n263\n"
+
+func newServer(t *testing.T, s *Server) *httptest.Server {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// Sends a request and decodes the JSON response into v
+func do(t *testing.T, method string, url string, body string, v interface{}) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s %s: got content type %q", method, url, ct)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("%s %s: %v", method, url, err)
+	}
+	return resp
+}
+
+func TestDays(t *testing.T) {
+	server := newServer(t, New())
+	var days Days
+	resp := do(t, http.MethodGet, server.URL+"/days", "", &days)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("got status %d", resp.StatusCode)
+	}
+	if len(days.Days) != 1 || days.Days[0] != 1 {
+		t.Errorf("got days %v, expected [1]", days.Days)
+	}
+}
+
+func TestSolve(t *testing.T) {
+	server := newServer(t, New())
+	for part, expected := range map[int]int{1: 7, 2: 5} {
+		var answer Answer
+		resp := do(t, http.MethodPost, fmt.Sprintf("%s/days/1/parts/%d", server.URL, part), example, &answer)
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("part %d: got status %d", part, resp.StatusCode)
+		}
+		if answer.Day != 1 || answer.Part != part || !answer.Answer.Equal(aoc.Int(expected)) || answer.Error != "" {
+			t.Errorf("part %d: got %+v, expected answer %d", part, answer, expected)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := New()
+	s.MaxInputSize = int64(len(example))
+	server := newServer(t, s)
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		error  string
+	}{
+		{"unknown endpoint", http.MethodGet, "/answers", "", http.StatusNotFound, "no such endpoint"},
+		{"unknown day", http.MethodPost, "/days/25/parts/1", example, http.StatusNotFound, "day 25"},
+		{"invalid day", http.MethodPost, "/days/one/parts/1", example, http.StatusNotFound, "day one"},
+		{"invalid part", http.MethodPost, "/days/1/parts/3", example, http.StatusNotFound, "part 3"},
+		{"wrong method", http.MethodGet, "/days/1/parts/1", "", http.StatusMethodNotAllowed, "use POST"},
+		{"too large", http.MethodPost, "/days/1/parts/1", example + "1\n", http.StatusRequestEntityTooLarge, "larger than"},
+		{"parse error", http.MethodPost, "/days/1/parts/1", "199\nabc\n", http.StatusUnprocessableEntity, "line 2"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var body struct {
+				Error string `json:"error"`
+			}
+			resp := do(t, test.method, server.URL+test.path, test.body, &body)
+			if resp.StatusCode != test.status {
+				t.Errorf("got status %d, expected %d", resp.StatusCode, test.status)
+			}
+			if !strings.Contains(body.Error, test.error) {
+				t.Errorf("got error %q, expected it to contain %q", body.Error, test.error)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	s := New()
+	s.Timeout = time.Nanosecond
+	server := newServer(t, s)
+	var answer Answer
+	resp := do(t, http.MethodPost, server.URL+"/days/1/parts/1", example, &answer)
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got status %d", resp.StatusCode)
+	}
+	if !strings.Contains(answer.Error, "timed out") {
+		t.Errorf("got error %q", answer.Error)
+	}
+}
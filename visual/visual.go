@@ -0,0 +1,161 @@
+// Package visual plays the animations of the days in the terminal
+//
+// A day animates the solving of its puzzle by drawing frames, plain text
+// that may use the colors of this package, and showing them one after the
+// other. The player clears the terminal between two frames with ANSI escape
+// codes, and lets the user change the speed, pause and step through the frames
+package visual
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// ErrQuit is returned by Show when the user asked to stop the animation
+var ErrQuit = errors.New("animation stopped")
+
+// ANSI escape codes
+const (
+	clearScreen = "\x1b[H\x1b[2J"
+	bold        = "\x1b[1m"
+	yellow      = "\x1b[1;33m"
+	green       = "\x1b[1;32m"
+	faint       = "\x1b[2m"
+	reset       = "\x1b[0m"
+)
+
+// Highlight returns s in bold yellow, for what changes in a frame
+func Highlight(s string) string {
+	return yellow + s + reset
+}
+
+// Win returns s in bold green, for what the puzzle is looking for
+func Win(s string) string {
+	return green + s + reset
+}
+
+// Faint returns s dimmed, for what no longer matters
+func Faint(s string) string {
+	return faint + s + reset
+}
+
+// Bold returns s in bold
+func Bold(s string) string {
+	return bold + s + reset
+}
+
+// The fastest and slowest the animation can go
+const (
+	minDelay = time.Millisecond
+	maxDelay = 10 * time.Second
+)
+
+// Player shows the frames of an animation, waiting between two of them
+// The terminal stays in line mode, so the commands are typed followed by Enter:
+// an empty line pauses or, once paused, steps to the next frame, p resumes,
+// + and - change the speed and q stops
+type Player struct {
+	ctx      context.Context
+	out      io.Writer
+	delay    time.Duration
+	paused   bool
+	frames   int
+	commands chan string
+}
+
+// NewPlayer returns a player writing the frames to out, fps frames per second,
+// that reads the commands of the user from in
+// When paused is true, it waits for the user before showing each frame
+func NewPlayer(ctx context.Context, out io.Writer, in io.Reader, fps float64, paused bool) *Player {
+	delay := maxDelay
+	if fps > 0 {
+		delay = clampDelay(time.Duration(float64(time.Second) / fps))
+	}
+	p := &Player{ctx: ctx, out: out, delay: delay, paused: paused, commands: make(chan string)}
+	go func() {
+		scanner := bufio.NewScanner(in)
+		for scanner.Scan() {
+			select {
+			case p.commands <- strings.TrimSpace(scanner.Text()):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return p
+}
+
+// Show draws a frame, then waits for the time between two frames, or for the
+// user to step to the next one when paused
+// It returns ErrQuit when the user stops the animation, and the error of the
+// context when it is done
+func (p *Player) Show(frame string) error {
+	p.frames++
+	p.draw(frame)
+	var tick <-chan time.Time
+	for {
+		if !p.paused && tick == nil {
+			tick = time.After(p.delay)
+		}
+		select {
+		case <-p.ctx.Done():
+			return p.ctx.Err()
+		case <-tick:
+			return nil
+		case command := <-p.commands:
+			switch command {
+			case "":
+				if p.paused {
+					return nil
+				}
+				p.paused = true
+				tick = nil
+			case "p":
+				p.paused = false
+			case "+":
+				p.delay = clampDelay(p.delay / 2)
+				tick = nil
+			case "-":
+				p.delay = clampDelay(p.delay * 2)
+				tick = nil
+			case "q":
+				return ErrQuit
+			}
+			p.draw(frame)
+		}
+	}
+}
+
+func (p *Player) draw(frame string) {
+	state := fmt.Sprintf("playing, %s per frame", p.delay)
+	help := "Enter: pause  +/-: speed  q: quit"
+	if p.paused {
+		state = "paused"
+		help = "Enter: next frame  p: resume  +/-: speed  q: quit"
+	}
+	fmt.Fprintf(p.out, "%s%s\n\n%s\n", clearScreen, frame, Faint(fmt.Sprintf("frame %d, %s - %s", p.frames, state, help)))
+}
+
+func clampDelay(d time.Duration) time.Duration {
+	if d < minDelay {
+		return minDelay
+	}
+	if d > maxDelay {
+		return maxDelay
+	}
+	return d
+}
+
+// Scale maps value, between min and max, to a position between 0 and size-1
+// It is used to fit large coordinates in the terminal
+func Scale(value int, min int, max int, size int) int {
+	if max <= min || size <= 1 {
+		return 0
+	}
+	return (value - min) * (size - 1) / (max - min)
+}
@@ -0,0 +1,62 @@
+package visual
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStep(t *testing.T) {
+	var out bytes.Buffer
+	p := NewPlayer(context.Background(), &out, strings.NewReader("\n\nq\n"), 1, true)
+	for _, frame := range []string{"first", "second"} {
+		if err := p.Show(frame); err != nil {
+			t.Fatalf("%s: %v", frame, err)
+		}
+	}
+	if err := p.Show("third"); err != ErrQuit {
+		t.Fatalf("got %v, expected %v", err, ErrQuit)
+	}
+	if !strings.Contains(out.String(), clearScreen+"second\n") {
+		t.Errorf("the second frame is not drawn on a cleared screen: %q", out.String())
+	}
+}
+
+func TestPlay(t *testing.T) {
+	var out bytes.Buffer
+	// Nothing to read, the frames follow each other on their own
+	p := NewPlayer(context.Background(), &out, strings.NewReader(""), 1000, false)
+	for i := 0; i < 3; i++ {
+		if err := p.Show("frame"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := strings.Count(out.String(), clearScreen); n != 3 {
+		t.Errorf("got %d frames drawn, expected 3", n)
+	}
+}
+
+func TestCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := NewPlayer(ctx, &bytes.Buffer{}, strings.NewReader(""), 1, true)
+	cancel()
+	if err := p.Show("frame"); err != context.Canceled {
+		t.Errorf("got %v, expected %v", err, context.Canceled)
+	}
+}
+
+func TestScale(t *testing.T) {
+	tests := []struct{ value, min, max, size, expected int }{
+		{0, 0, 10, 11, 0},
+		{10, 0, 10, 11, 10},
+		{500, 0, 1000, 70, 34},
+		{1000, 0, 1000, 70, 69},
+		{5, 5, 5, 10, 0},
+	}
+	for _, test := range tests {
+		if got := Scale(test.value, test.min, test.max, test.size); got != test.expected {
+			t.Errorf("Scale(%d, %d, %d, %d) = %d, expected %d", test.value, test.min, test.max, test.size, got, test.expected)
+		}
+	}
+}